@@ -0,0 +1,138 @@
+// cache/cache.go
+//
+// Cópias locais de objetos abertos em aplicativos externos, guardadas na
+// pasta de cache do sistema (ex: ~/.cache/s3nd-files/open) com limite de
+// tamanho.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Limite padrão do cache e idade a partir da qual cópias são descartadas
+const (
+	DefaultLimit = 1 << 30
+	MaxAge       = 7 * 24 * time.Hour
+)
+
+// Cache organiza as cópias em uma subpasta por objeto
+type Cache struct {
+	dir   string
+	limit int64
+
+	mu     sync.Mutex
+	pinned map[string]bool
+}
+
+// Open usa a pasta de cache padrão
+func Open() (*Cache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("falha ao localizar pasta de cache: %w", err)
+	}
+	return New(filepath.Join(dir, "s3nd-files", "open"), DefaultLimit)
+}
+
+// New usa dir, mantendo no máximo limit bytes
+func New(dir string, limit int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("falha ao criar pasta de cache: %w", err)
+	}
+	return &Cache{dir: dir, limit: limit, pinned: make(map[string]bool)}, nil
+}
+
+// Path retorna onde guardar a cópia de bucket/key. O arquivo mantém o nome
+// original para que o aplicativo certo seja escolhido pela extensão.
+func (c *Cache) Path(bucket, key string) string {
+	sum := sha256.Sum256([]byte(bucket + "/" + key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:8]), filepath.Base(key))
+}
+
+// Pin protege path da limpeza enquanto estiver aberto
+func (c *Cache) Pin(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pinned[filepath.Dir(path)] = true
+}
+
+// Unpin libera path para a limpeza
+func (c *Cache) Unpin(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pinned, filepath.Dir(path))
+}
+
+// Pinned informa se path está fixado
+func (c *Cache) Pinned(path string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pinned[filepath.Dir(path)]
+}
+
+// entry é a subpasta de um objeto no cache
+type entry struct {
+	dir     string
+	size    int64
+	modTime time.Time
+}
+
+// Prune remove cópias mais antigas que MaxAge e, se o cache ainda passar do
+// limite, as menos usadas recentemente. Cópias fixadas com Pin ficam.
+func (c *Cache) Prune() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dirs, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("falha ao ler cache: %w", err)
+	}
+
+	var entries []entry
+	var total int64
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		e := entry{dir: filepath.Join(c.dir, d.Name())}
+		filepath.WalkDir(e.dir, func(_ string, f os.DirEntry, err error) error {
+			if err != nil || f.IsDir() {
+				return nil
+			}
+			if info, err := f.Info(); err == nil {
+				e.size += info.Size()
+				if info.ModTime().After(e.modTime) {
+					e.modTime = info.ModTime()
+				}
+			}
+			return nil
+		})
+		entries = append(entries, e)
+		total += e.size
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	now := time.Now()
+	for _, e := range entries {
+		if c.pinned[e.dir] {
+			continue
+		}
+		if total <= c.limit && now.Sub(e.modTime) < MaxAge {
+			continue
+		}
+		if err := os.RemoveAll(e.dir); err != nil {
+			return fmt.Errorf("falha ao limpar cache: %w", err)
+		}
+		total -= e.size
+	}
+	return nil
+}
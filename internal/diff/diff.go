@@ -0,0 +1,160 @@
+// diff/diff.go
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind indica se uma linha foi mantida, removida ou adicionada
+type Kind int
+
+const (
+	Equal Kind = iota
+	Delete
+	Insert
+)
+
+// Line é uma linha do resultado da comparação
+type Line struct {
+	Kind Kind
+	Text string
+}
+
+// Lines compara dois textos linha a linha (maior subsequência comum).
+// O custo é O(n*m), adequado para arquivos de configuração e políticas.
+func Lines(a, b string) []Line {
+	x := splitLines(a)
+	y := splitLines(b)
+
+	// lcs[i][j] = tamanho da maior subsequência comum de x[i:] e y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []Line
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			out = append(out, Line{Equal, x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, Line{Delete, x[i]})
+			i++
+		default:
+			out = append(out, Line{Insert, y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		out = append(out, Line{Delete, x[i]})
+	}
+	for ; j < len(y); j++ {
+		out = append(out, Line{Insert, y[j]})
+	}
+	return out
+}
+
+// Changed indica se há alguma linha adicionada ou removida
+func Changed(lines []Line) bool {
+	for _, l := range lines {
+		if l.Kind != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+// Format gera um texto no estilo diff unificado, mostrando context linhas
+// inalteradas ao redor de cada mudança
+func Format(lines []Line, context int) string {
+	show := make([]bool, len(lines))
+	for i, l := range lines {
+		if l.Kind == Equal {
+			continue
+		}
+		for k := max(0, i-context); k <= min(len(lines)-1, i+context); k++ {
+			show[k] = true
+		}
+	}
+
+	var b strings.Builder
+	skipped := false
+	for i, l := range lines {
+		if !show[i] {
+			skipped = true
+			continue
+		}
+		if skipped && b.Len() > 0 {
+			b.WriteString("  ...\n")
+		}
+		skipped = false
+
+		prefix := "  "
+		switch l.Kind {
+		case Delete:
+			prefix = "- "
+		case Insert:
+			prefix = "+ "
+		}
+		fmt.Fprintf(&b, "%s%s\n", prefix, l.Text)
+	}
+	return b.String()
+}
+
+// Conflicts junta os dois lados de Lines(a, b): linhas iguais ficam como
+// estão e cada trecho divergente vira um bloco com marcadores de conflito,
+// com o lado a rotulado labelA e o lado b rotulado labelB
+func Conflicts(lines []Line, labelA, labelB string) string {
+	var b strings.Builder
+	var onlyA, onlyB []string
+
+	flush := func() {
+		if len(onlyA) == 0 && len(onlyB) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "<<<<<<< %s\n", labelA)
+		for _, l := range onlyA {
+			b.WriteString(l + "\n")
+		}
+		b.WriteString("=======\n")
+		for _, l := range onlyB {
+			b.WriteString(l + "\n")
+		}
+		fmt.Fprintf(&b, ">>>>>>> %s\n", labelB)
+		onlyA, onlyB = nil, nil
+	}
+
+	for _, l := range lines {
+		switch l.Kind {
+		case Delete:
+			onlyA = append(onlyA, l.Text)
+		case Insert:
+			onlyB = append(onlyB, l.Text)
+		default:
+			flush()
+			b.WriteString(l.Text + "\n")
+		}
+	}
+	flush()
+	return b.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
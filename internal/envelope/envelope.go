@@ -0,0 +1,209 @@
+// envelope/envelope.go
+//
+// Criptografia no cliente por envelope: cada objeto recebe uma chave de
+// dados aleatória, usada para cifrar o conteúdo em blocos AES-GCM. A chave
+// de dados é cifrada ("embrulhada") com a chave mestra do usuário, derivada
+// de uma senha ou lida de um arquivo, e guardada nos metadados do objeto.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+
+	"s3nd-files/internal/models"
+)
+
+// Chaves de metadados (sem o prefixo x-amz-meta-) gravadas em cada objeto
+const (
+	MetaAlgorithm  = "s3nd-cse"
+	MetaWrappedKey = "s3nd-cse-key"
+	MetaKeySource  = "s3nd-cse-source"
+	MetaSalt       = "s3nd-cse-salt"
+	MetaSize       = "s3nd-cse-size"
+)
+
+// Algorithm identifica o formato em MetaAlgorithm
+const Algorithm = "AES-256-GCM-STREAM-v1"
+
+// ContentType marca objetos cifrados para outras ferramentas
+const ContentType = "application/x-s3nd-encrypted"
+
+const (
+	sourcePassphrase = "passphrase"
+	sourceKeyFile    = "keyfile"
+
+	pbkdf2Iterations = 600000
+	wrapAAD          = "s3nd-cse-key-v1"
+)
+
+// ErrWrongKey indica que a chave mestra não abre a chave de dados do objeto
+var ErrWrongKey = errors.New("a chave do cliente não corresponde à usada para cifrar o objeto")
+
+// MasterKey embrulha e desembrulha chaves de dados
+type MasterKey struct {
+	source     string
+	passphrase string
+
+	mu sync.Mutex
+	// Chave derivada de cada salt já usado (arquivo: salt vazio)
+	keys map[string][]byte
+	salt string
+}
+
+// FromPassphrase cria uma chave mestra derivada de uma senha (PBKDF2-SHA256)
+func FromPassphrase(passphrase string) (*MasterKey, error) {
+	if len(passphrase) < 8 {
+		return nil, fmt.Errorf("a senha deve ter ao menos 8 caracteres")
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	m := &MasterKey{
+		source:     sourcePassphrase,
+		passphrase: passphrase,
+		keys:       make(map[string][]byte),
+		salt:       base64.StdEncoding.EncodeToString(salt),
+	}
+	if _, err := m.key(m.salt); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FromKeyFile lê uma chave mestra de 32 bytes (binário, base64 ou hex)
+func FromKeyFile(path string) (*MasterKey, error) {
+	key, err := models.LoadKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &MasterKey{
+		source: sourceKeyFile,
+		keys:   map[string][]byte{"": key},
+	}, nil
+}
+
+// key retorna a chave mestra para o salt, derivando-a se preciso
+func (m *MasterKey) key(salt string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if key, ok := m.keys[salt]; ok {
+		return key, nil
+	}
+	if m.source != sourcePassphrase {
+		return nil, ErrWrongKey
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return nil, fmt.Errorf("salt inválido nos metadados: %w", err)
+	}
+	key, err := pbkdf2.Key(sha256.New, m.passphrase, raw, pbkdf2Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	m.keys[salt] = key
+	return key, nil
+}
+
+// NewDataKey gera uma chave de dados e os metadados que a acompanham
+// (chave embrulhada, origem da chave mestra e salt)
+func (m *MasterKey) NewDataKey() ([]byte, map[string]string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, err
+	}
+
+	master, err := m.key(m.salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	gcm, err := newGCM(master)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	wrapped := gcm.Seal(nonce, nonce, dataKey, []byte(wrapAAD))
+
+	meta := map[string]string{
+		MetaAlgorithm:  Algorithm,
+		MetaWrappedKey: base64.StdEncoding.EncodeToString(wrapped),
+		MetaKeySource:  m.source,
+	}
+	if m.salt != "" {
+		meta[MetaSalt] = m.salt
+	}
+	return dataKey, meta, nil
+}
+
+// DataKey desembrulha a chave de dados a partir dos metadados do objeto
+func (m *MasterKey) DataKey(meta map[string]string) ([]byte, error) {
+	if meta[MetaAlgorithm] != Algorithm {
+		return nil, fmt.Errorf("formato de criptografia não suportado: %q", meta[MetaAlgorithm])
+	}
+	if meta[MetaKeySource] != m.source {
+		return nil, fmt.Errorf("%w (objeto cifrado com %s)", ErrWrongKey, sourceName(meta[MetaKeySource]))
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(meta[MetaWrappedKey])
+	if err != nil {
+		return nil, fmt.Errorf("chave embrulhada inválida nos metadados: %w", err)
+	}
+
+	master, err := m.key(meta[MetaSalt])
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(master)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, ErrWrongKey
+	}
+
+	dataKey, err := gcm.Open(nil, wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():], []byte(wrapAAD))
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return dataKey, nil
+}
+
+// IsEncrypted verifica se os metadados marcam um objeto cifrado no cliente
+func IsEncrypted(meta map[string]string) bool {
+	_, ok := meta[MetaAlgorithm]
+	return ok
+}
+
+func sourceName(source string) string {
+	switch source {
+	case sourcePassphrase:
+		return "senha"
+	case sourceKeyFile:
+		return "arquivo de chave"
+	default:
+		return source
+	}
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
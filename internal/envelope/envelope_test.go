@@ -0,0 +1,217 @@
+package envelope
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sealedChunk = streamChunkSize + 16
+
+func keyFile(t *testing.T) *MasterKey {
+	t.Helper()
+	raw := make([]byte, 32)
+	rand.Read(raw)
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(raw)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := FromKeyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
+
+func encrypt(t *testing.T, plain, dataKey []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	n, err := Encrypt(&buf, bytes.NewReader(plain), dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(plain)) {
+		t.Fatalf("Encrypt retornou %d, esperado %d", n, len(plain))
+	}
+	return buf.Bytes()
+}
+
+func decrypt(sealed, dataKey []byte) ([]byte, error) {
+	r, err := NewReader(bytes.NewReader(sealed), dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestRoundTrip(t *testing.T) {
+	dataKey := randomBytes(32)
+	for _, size := range []int{0, 1, streamChunkSize - 1, streamChunkSize, streamChunkSize + 1, 3*streamChunkSize + 7} {
+		plain := randomBytes(size)
+		sealed := encrypt(t, plain, dataKey)
+		if size > 0 && int64(len(sealed)) > EncryptedPrefix(int64(size)) {
+			t.Errorf("tamanho %d: cifrado com %d bytes, EncryptedPrefix = %d", size, len(sealed), EncryptedPrefix(int64(size)))
+		}
+		got, err := decrypt(sealed, dataKey)
+		if err != nil {
+			t.Fatalf("tamanho %d: %v", size, err)
+		}
+		if !bytes.Equal(got, plain) {
+			t.Errorf("tamanho %d: conteúdo decifrado difere do original", size)
+		}
+	}
+}
+
+func TestPartialRead(t *testing.T) {
+	dataKey := randomBytes(32)
+	plain := randomBytes(3 * streamChunkSize)
+	sealed := encrypt(t, plain, dataKey)
+
+	n := int64(streamChunkSize + 10)
+	r, err := NewReader(bytes.NewReader(sealed[:EncryptedPrefix(n)]), dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, n)
+	if _, err := io.ReadFull(r, got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plain[:n]) {
+		t.Error("leitura parcial difere do original")
+	}
+}
+
+func TestTampering(t *testing.T) {
+	dataKey := randomBytes(32)
+	plain := randomBytes(3*streamChunkSize + 100)
+	sealed := encrypt(t, plain, dataKey)
+	chunk := func(i int) []byte {
+		start := headerSize + i*sealedChunk
+		return sealed[start:min(start+sealedChunk, len(sealed))]
+	}
+	join := func(parts ...[]byte) []byte {
+		return bytes.Join(parts, nil)
+	}
+	header := sealed[:headerSize]
+
+	tests := []struct {
+		name   string
+		sealed []byte
+	}{
+		{"sem o último bloco", sealed[:headerSize+3*sealedChunk]},
+		{"último bloco cortado", sealed[:len(sealed)-1]},
+		{"só o cabeçalho", header},
+		{"cabeçalho cortado", sealed[:headerSize-1]},
+		{"blocos trocados", join(header, chunk(1), chunk(0), chunk(2), chunk(3))},
+		{"bloco repetido", join(header, chunk(0), chunk(0), chunk(2), chunk(3))},
+		{"bloco final no meio", join(header, chunk(0), chunk(3))},
+		{"byte alterado", func() []byte {
+			b := bytes.Clone(sealed)
+			b[headerSize+10] ^= 1
+			return b
+		}()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decrypt(tt.sealed, dataKey)
+			if !errors.Is(err, ErrCorrupted) {
+				t.Errorf("erro = %v, esperado ErrCorrupted", err)
+			}
+		})
+	}
+}
+
+func TestWrongDataKey(t *testing.T) {
+	sealed := encrypt(t, randomBytes(100), randomBytes(32))
+	if _, err := decrypt(sealed, randomBytes(32)); !errors.Is(err, ErrCorrupted) {
+		t.Errorf("erro = %v, esperado ErrCorrupted", err)
+	}
+}
+
+func TestDataKey(t *testing.T) {
+	m := keyFile(t)
+	dataKey, meta, err := m.NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(meta) {
+		t.Fatal("metadados sem a marca de objeto cifrado")
+	}
+	got, err := m.DataKey(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, dataKey) {
+		t.Error("chave de dados desembrulhada difere da original")
+	}
+}
+
+func TestWrongMasterKey(t *testing.T) {
+	pass, err := FromPassphrase("senha correta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := keyFile(t)
+
+	tests := []struct {
+		name  string
+		owner *MasterKey
+		other func(t *testing.T) *MasterKey
+	}{
+		{"outro arquivo", file, keyFile},
+		{"arquivo no lugar de senha", pass, keyFile},
+		{"senha no lugar de arquivo", file, func(t *testing.T) *MasterKey { return pass }},
+		{"outra senha", pass, func(t *testing.T) *MasterKey {
+			m, err := FromPassphrase("senha errada")
+			if err != nil {
+				t.Fatal(err)
+			}
+			return m
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, meta, err := tt.owner.NewDataKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tt.other(t).DataKey(meta); !errors.Is(err, ErrWrongKey) {
+				t.Errorf("erro = %v, esperado ErrWrongKey", err)
+			}
+		})
+	}
+}
+
+func TestPassphraseSalt(t *testing.T) {
+	// Outra instância com a mesma senha abre a chave pelo salt dos metadados
+	a, err := FromPassphrase("mesma senha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := FromPassphrase("mesma senha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataKey, meta, err := a.NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := b.DataKey(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, dataKey) {
+		t.Error("chave de dados desembrulhada difere da original")
+	}
+}
@@ -0,0 +1,192 @@
+// envelope/stream.go
+package envelope
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Formato do conteúdo cifrado:
+//
+//	cabeçalho: "S3NDCSE1" | tamanho do bloco (uint32) | prefixo do nonce (7 bytes)
+//	blocos:    AES-GCM(bloco de texto) — o último pode ser menor ou vazio
+//
+// O nonce de cada bloco é prefixo | contador (uint32) | 1 se for o último,
+// o que impede reordenar ou truncar blocos. O cabeçalho entra como dado
+// autenticado de todos os blocos.
+const (
+	streamMagic     = "S3NDCSE1"
+	streamChunkSize = 64 * 1024
+	noncePrefixSize = 7
+	headerSize      = len(streamMagic) + 4 + noncePrefixSize
+)
+
+// ErrCorrupted indica conteúdo cifrado truncado ou alterado
+var ErrCorrupted = errors.New("conteúdo cifrado corrompido ou incompleto")
+
+func chunkNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], counter)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// Encrypt cifra src em dst com a chave de dados e retorna o tamanho do
+// texto original
+func Encrypt(dst io.Writer, src io.Reader, dataKey []byte) (int64, error) {
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return 0, err
+	}
+
+	header := make([]byte, headerSize)
+	copy(header, streamMagic)
+	binary.BigEndian.PutUint32(header[len(streamMagic):], streamChunkSize)
+	prefix := header[len(streamMagic)+4:]
+	if _, err := rand.Read(prefix); err != nil {
+		return 0, err
+	}
+	if _, err := dst.Write(header); err != nil {
+		return 0, err
+	}
+
+	in := bufio.NewReaderSize(src, streamChunkSize)
+	plain := make([]byte, streamChunkSize)
+	sealed := make([]byte, 0, streamChunkSize+gcm.Overhead())
+	var total int64
+
+	for counter := uint32(0); ; counter++ {
+		if counter == ^uint32(0) {
+			return total, fmt.Errorf("arquivo grande demais para cifrar")
+		}
+
+		n, err := io.ReadFull(in, plain)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return total, err
+		}
+		total += int64(n)
+
+		// Um bloco cheio só é o último se não houver mais nada para ler
+		last := n < streamChunkSize
+		if !last {
+			if _, err := in.Peek(1); err == io.EOF {
+				last = true
+			} else if err != nil {
+				return total, err
+			}
+		}
+
+		sealed = gcm.Seal(sealed[:0], chunkNonce(prefix, counter, last), plain[:n], header)
+		if _, err := dst.Write(sealed); err != nil {
+			return total, err
+		}
+		if last {
+			return total, nil
+		}
+	}
+}
+
+// EncryptedPrefix retorna quantos bytes do início do conteúdo cifrado
+// bastam para decifrar os primeiros n bytes do original (leituras parciais)
+func EncryptedPrefix(n int64) int64 {
+	const tagSize = 16 // GCM
+	chunks := (n + streamChunkSize - 1) / streamChunkSize
+	// Um byte a mais mostra ao leitor que o último bloco lido não é o final
+	return int64(headerSize) + chunks*(streamChunkSize+tagSize) + 1
+}
+
+// reader decifra o conteúdo bloco a bloco
+type reader struct {
+	src    *bufio.Reader
+	gcm    cipher.AEAD
+	header []byte
+	prefix []byte
+
+	chunk   []byte
+	plain   []byte
+	counter uint32
+	done    bool
+}
+
+// NewReader retorna um leitor que decifra src com a chave de dados. Erros
+// de autenticação aparecem como ErrCorrupted durante a leitura.
+func NewReader(src io.Reader, dataKey []byte) (io.Reader, error) {
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(src, header); err != nil {
+		return nil, ErrCorrupted
+	}
+	if string(header[:len(streamMagic)]) != streamMagic {
+		return nil, fmt.Errorf("o conteúdo não está no formato cifrado do s3nd")
+	}
+	chunkSize := binary.BigEndian.Uint32(header[len(streamMagic):])
+	if chunkSize == 0 || chunkSize > 16*1024*1024 {
+		return nil, ErrCorrupted
+	}
+
+	return &reader{
+		src:    bufio.NewReaderSize(src, int(chunkSize)+gcm.Overhead()+1),
+		gcm:    gcm,
+		header: header,
+		prefix: header[len(streamMagic)+4:],
+		chunk:  make([]byte, int(chunkSize)+gcm.Overhead()),
+	}, nil
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+// next lê e decifra o próximo bloco
+func (r *reader) next() error {
+	n, err := io.ReadFull(r.src, r.chunk)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			// Faltou o bloco final
+			return ErrCorrupted
+		}
+		return err
+	}
+
+	last := n < len(r.chunk)
+	if !last {
+		if _, err := r.src.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	plain, err := r.gcm.Open(r.chunk[:0], chunkNonce(r.prefix, r.counter, last), r.chunk[:n], r.header)
+	if err != nil {
+		return ErrCorrupted
+	}
+
+	r.plain = plain
+	r.counter++
+	r.done = last
+	return nil
+}
@@ -0,0 +1,220 @@
+// filter/filter.go
+//
+// Regras que decidem quais arquivos de uma pasta local entram na seleção e
+// nos envios: padrões de inclusão e exclusão no formato do .gitignore, os
+// próprios arquivos .gitignore/.s3ndignore e limites de tamanho e idade.
+package filter
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Nomes dos arquivos de padrões lidos em cada pasta
+const (
+	GitIgnoreFile  = ".gitignore"
+	S3ndIgnoreFile = ".s3ndignore"
+)
+
+// Rules são os filtros aplicados ao percorrer uma pasta
+type Rules struct {
+	// Include, se não vazio, restringe os arquivos aos que casam com algum padrão
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+	// Ler os padrões de .gitignore e .s3ndignore de cada pasta
+	GitIgnore  bool `json:"gitignore,omitempty"`
+	S3ndIgnore bool `json:"s3ndignore,omitempty"`
+	// Limites de tamanho em bytes (0 = sem limite)
+	MinSize int64 `json:"minSize,omitempty"`
+	MaxSize int64 `json:"maxSize,omitempty"`
+	// MaxAgeDays mantém só arquivos modificados nos últimos N dias e
+	// MinAgeDays só os modificados há pelo menos N dias (0 = sem limite)
+	MaxAgeDays int `json:"maxAgeDays,omitempty"`
+	MinAgeDays int `json:"minAgeDays,omitempty"`
+}
+
+// DefaultRules deixam de fora controle de versão, dependências e arquivos
+// temporários ou de sistema
+func DefaultRules() Rules {
+	return Rules{
+		Exclude: []string{
+			".git/", ".svn/", ".hg/", "node_modules/", "__pycache__/",
+			".DS_Store", "Thumbs.db", "desktop.ini",
+			"*.tmp", "*.temp", "*.swp", "*~", "~$*",
+		},
+		GitIgnore:  true,
+		S3ndIgnore: true,
+	}
+}
+
+// Entry é um arquivo encontrado, ou uma pasta excluída inteira
+type Entry struct {
+	Path    string
+	Dir     bool
+	Size    int64
+	ModTime time.Time
+	// Reason explica a exclusão; vazio para arquivos incluídos
+	Reason string
+}
+
+// Excluded informa se a entrada ficou de fora
+func (e Entry) Excluded() bool { return e.Reason != "" }
+
+// matcher guarda os padrões compilados de uma varredura
+type matcher struct {
+	rules   Rules
+	now     time.Time
+	include []pattern
+	exclude []pattern
+	// Padrões dos arquivos de ignorar, pela pasta (relativa à raiz) onde estão
+	ignores map[string][]pattern
+}
+
+// Walk percorre root (pasta ou arquivo) e chama fn para cada arquivo,
+// incluído ou não. Pastas excluídas são informadas uma vez e não são
+// percorridas.
+func Walk(root string, rules Rules, now time.Time, fn func(Entry)) error {
+	m := &matcher{
+		rules:   rules,
+		now:     now,
+		include: parsePatterns(rules.Include, "incluir"),
+		exclude: parsePatterns(rules.Exclude, "regra"),
+		ignores: make(map[string][]pattern),
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return fmt.Errorf("falha ao ler %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		// Arquivo avulso: só o nome é comparado
+		fn(m.entry(root, filepath.Base(root), info))
+		return nil
+	}
+
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p != root {
+				fn(Entry{Path: p, Dir: d != nil && d.IsDir(), Reason: "ilegível: " + err.Error()})
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			m.loadIgnores(p, rel)
+			return nil
+		}
+
+		if d.IsDir() {
+			if reason := m.excludedBy(rel, true); reason != "" {
+				fn(Entry{Path: p, Dir: true, Reason: reason})
+				return filepath.SkipDir
+			}
+			m.loadIgnores(p, rel)
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			fn(Entry{Path: p, Reason: "ilegível: " + err.Error()})
+			return nil
+		}
+		fn(m.entry(p, rel, info))
+		return nil
+	})
+}
+
+// loadIgnores lê os arquivos de padrões da pasta dir (rel em relação à raiz)
+func (m *matcher) loadIgnores(dir, rel string) {
+	var patterns []pattern
+	if m.rules.GitIgnore {
+		patterns = append(patterns, readPatternFile(filepath.Join(dir, GitIgnoreFile))...)
+	}
+	if m.rules.S3ndIgnore {
+		patterns = append(patterns, readPatternFile(filepath.Join(dir, S3ndIgnoreFile))...)
+	}
+	if len(patterns) > 0 {
+		m.ignores[rel] = patterns
+	}
+}
+
+// excludedBy retorna o padrão que exclui rel, ou "". Vale o último padrão
+// que casar; os arquivos de ignorar mais internos vêm por último.
+func (m *matcher) excludedBy(rel string, isDir bool) string {
+	reason := ""
+	apply := func(patterns []pattern, relTo string) {
+		for _, p := range patterns {
+			if !p.match(relTo, isDir) {
+				continue
+			}
+			if p.negate {
+				reason = ""
+			} else {
+				reason = p.source
+			}
+		}
+	}
+
+	apply(m.exclude, rel)
+	apply(m.ignores["."], rel)
+	if dir := path.Dir(rel); dir != "." {
+		parts := strings.Split(dir, "/")
+		for i := range parts {
+			base := strings.Join(parts[:i+1], "/")
+			if patterns, ok := m.ignores[base]; ok {
+				apply(patterns, strings.TrimPrefix(rel, base+"/"))
+			}
+		}
+	}
+	return reason
+}
+
+// entry aplica inclusão, tamanho e idade a um arquivo não excluído
+func (m *matcher) entry(p, rel string, info fs.FileInfo) Entry {
+	e := Entry{Path: p, Size: info.Size(), ModTime: info.ModTime()}
+	if reason := m.excludedBy(rel, false); reason != "" {
+		e.Reason = reason
+		return e
+	}
+
+	if len(m.include) > 0 {
+		included := false
+		for _, pat := range m.include {
+			if pat.match(rel, false) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			e.Reason = "não corresponde aos padrões incluídos"
+			return e
+		}
+	}
+
+	age := m.now.Sub(info.ModTime())
+	switch {
+	case m.rules.MinSize > 0 && info.Size() < m.rules.MinSize:
+		e.Reason = "abaixo do tamanho mínimo"
+	case m.rules.MaxSize > 0 && info.Size() > m.rules.MaxSize:
+		e.Reason = "acima do tamanho máximo"
+	case m.rules.MaxAgeDays > 0 && age > days(m.rules.MaxAgeDays):
+		e.Reason = fmt.Sprintf("modificado há mais de %d dia(s)", m.rules.MaxAgeDays)
+	case m.rules.MinAgeDays > 0 && age < days(m.rules.MinAgeDays):
+		e.Reason = fmt.Sprintf("modificado há menos de %d dia(s)", m.rules.MinAgeDays)
+	}
+	return e
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
@@ -0,0 +1,303 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		line   string
+		rel    string
+		isDir  bool
+		ok     bool
+		match  bool
+		negate bool
+	}{
+		{line: "", ok: false},
+		{line: "   ", ok: false},
+		{line: "# comentário", ok: false},
+		{line: "/", ok: false},
+		{line: "*.log", rel: "a/b/x.log", ok: true, match: true},
+		{line: "*.log", rel: "x.txt", ok: true, match: false},
+		{line: "*.log", rel: "logs", isDir: true, ok: true, match: false},
+		{line: "build/", rel: "build", isDir: true, ok: true, match: true},
+		{line: "build/", rel: "a/build", isDir: true, ok: true, match: true},
+		{line: "build/", rel: "build", isDir: false, ok: true, match: false},
+		{line: "/raiz.txt", rel: "raiz.txt", ok: true, match: true},
+		{line: "/raiz.txt", rel: "sub/raiz.txt", ok: true, match: false},
+		{line: "docs/*.md", rel: "docs/a.md", ok: true, match: true},
+		{line: "docs/*.md", rel: "docs/a/b.md", ok: true, match: false},
+		{line: "docs/**/*.md", rel: "docs/a/b/c.md", ok: true, match: true},
+		{line: "docs/**/*.md", rel: "docs/c.md", ok: true, match: true},
+		{line: "docs/**/*.md", rel: "outro/docs/c.md", ok: true, match: false},
+		{line: "**/bar", rel: "a/b/bar", ok: true, match: true},
+		{line: "**/bar", rel: "bar", ok: true, match: true},
+		{line: "foo/**", rel: "foo/x/y", ok: true, match: true},
+		{line: "foo/**", rel: "foobar/x", ok: true, match: false},
+		{line: "a?c", rel: "abc", ok: true, match: true},
+		{line: "a?c", rel: "abbc", ok: true, match: false},
+		{line: "[!a]*.txt", rel: "b.txt", ok: true, match: true},
+		{line: "[!a]*.txt", rel: "a.txt", ok: true, match: false},
+		{line: "[abc", rel: "[abc", ok: true, match: true},
+		{line: "a.b", rel: "axb", ok: true, match: false},
+		{line: `\#arquivo`, rel: "#arquivo", ok: true, match: true},
+		{line: "!keep.log", rel: "keep.log", ok: true, match: true, negate: true},
+		{line: "*.log  ", rel: "x.log", ok: true, match: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line+"|"+tt.rel, func(t *testing.T) {
+			p, ok := parsePattern(tt.line, "teste")
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, esperado %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if p.negate != tt.negate {
+				t.Errorf("negate = %v, esperado %v", p.negate, tt.negate)
+			}
+			if got := p.match(tt.rel, tt.isDir); got != tt.match {
+				t.Errorf("match(%q, %v) = %v, esperado %v", tt.rel, tt.isDir, got, tt.match)
+			}
+		})
+	}
+}
+
+func TestParseGlob(t *testing.T) {
+	tests := []struct {
+		text  string
+		rel   string
+		err   bool
+		match bool
+	}{
+		{text: "*.html", rel: "site/index.html", match: true},
+		{text: "assets/**", rel: "assets/css/a.css", match: true},
+		{text: "assets/**", rel: "site/assets/a.css", match: false},
+		{text: "", err: true},
+		{text: "# comentário", err: true},
+		{text: "!*.html", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			g, err := ParseGlob(tt.text)
+			if (err != nil) != tt.err {
+				t.Fatalf("erro = %v, esperado erro: %v", err, tt.err)
+			}
+			if err == nil && g.Match(tt.rel) != tt.match {
+				t.Errorf("Match(%q) = %v, esperado %v", tt.rel, !tt.match, tt.match)
+			}
+		})
+	}
+	if (Glob{}).Match("a") {
+		t.Error("Glob vazio não deve casar com nada")
+	}
+}
+
+// writeTree cria os arquivos (caminho relativo → conteúdo) sob uma pasta temporária
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for rel, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// walkReasons percorre root e devolve o motivo de exclusão de cada entrada
+// pelo caminho relativo ("" para incluídas)
+func walkReasons(t *testing.T, root string, rules Rules, now time.Time) map[string]string {
+	t.Helper()
+	got := make(map[string]string)
+	err := Walk(root, rules, now, func(e Entry) {
+		rel, err := filepath.Rel(root, e.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[filepath.ToSlash(rel)] = e.Reason
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestWalk(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"a.txt":             "0123456789",
+		"b.log":             "x",
+		"keep.log":          "keep",
+		".gitignore":        "*.log\n!keep.log\n",
+		"build/out.bin":     "bin",
+		"src/main.go":       "package main\n",
+		"src/.s3ndignore":   "*.go\n",
+		"src/readme.md":     "# readme",
+		"node_modules/x.js": "js",
+		"old.txt":           "old",
+	})
+	now := time.Now()
+	old := now.AddDate(0, 0, -60)
+	if err := os.Chtimes(filepath.Join(root, "old.txt"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	defaults := DefaultRules()
+	defaults.Exclude = append(defaults.Exclude, "build/")
+
+	tests := []struct {
+		name  string
+		rules Rules
+		// Só as entradas listadas são conferidas
+		want map[string]string
+	}{
+		{
+			name:  "regras padrão e arquivos de ignorar",
+			rules: defaults,
+			want: map[string]string{
+				"a.txt":           "",
+				"b.log":           ".gitignore: *.log",
+				"keep.log":        "",
+				"build":           "regra: build/",
+				"src/main.go":     ".s3ndignore: *.go",
+				"src/.s3ndignore": "",
+				"src/readme.md":   "",
+				"node_modules":    "regra: node_modules/",
+			},
+		},
+		{
+			name:  "sem regras",
+			rules: Rules{},
+			want: map[string]string{
+				"b.log":             "",
+				"build/out.bin":     "",
+				"src/main.go":       "",
+				"node_modules/x.js": "",
+			},
+		},
+		{
+			name:  "exclusão reincluída na regra seguinte",
+			rules: Rules{Exclude: []string{"*.txt", "!a.txt"}},
+			want: map[string]string{
+				"a.txt":   "",
+				"old.txt": "regra: *.txt",
+			},
+		},
+		{
+			name:  "inclusão",
+			rules: Rules{Include: []string{"*.md", "/a.txt"}},
+			want: map[string]string{
+				"a.txt":         "",
+				"src/readme.md": "",
+				"old.txt":       "não corresponde aos padrões incluídos",
+				"src/main.go":   "não corresponde aos padrões incluídos",
+			},
+		},
+		{
+			name:  "exclusão prevalece sobre inclusão",
+			rules: Rules{Include: []string{"*.md"}, Exclude: []string{"src/"}},
+			want: map[string]string{
+				"src": "regra: src/",
+			},
+		},
+		{
+			name:  "tamanho",
+			rules: Rules{MinSize: 4, MaxSize: 10},
+			want: map[string]string{
+				"b.log":       "abaixo do tamanho mínimo",
+				"keep.log":    "",
+				"a.txt":       "",
+				"src/main.go": "acima do tamanho máximo",
+			},
+		},
+		{
+			name:  "idade máxima",
+			rules: Rules{MaxAgeDays: 30},
+			want: map[string]string{
+				"a.txt":   "",
+				"old.txt": "modificado há mais de 30 dia(s)",
+			},
+		},
+		{
+			name:  "idade mínima",
+			rules: Rules{MinAgeDays: 30},
+			want: map[string]string{
+				"a.txt":   "modificado há menos de 30 dia(s)",
+				"old.txt": "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := walkReasons(t, root, tt.rules, now)
+			for rel, want := range tt.want {
+				reason, found := got[rel]
+				if !found {
+					t.Errorf("%s não foi informado", rel)
+					continue
+				}
+				if reason != want {
+					t.Errorf("%s: motivo = %q, esperado %q", rel, reason, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWalkSkipsExcludedDirs(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"build/a/b.bin": "x",
+		"keep.txt":      "x",
+	})
+	got := walkReasons(t, root, Rules{Exclude: []string{"build/"}}, time.Now())
+	if _, found := got["build/a/b.bin"]; found {
+		t.Error("arquivos de uma pasta excluída não devem ser percorridos")
+	}
+	if got["build"] != "regra: build/" {
+		t.Errorf("build: motivo = %q", got["build"])
+	}
+}
+
+func TestWalkSingleFile(t *testing.T) {
+	root := writeTree(t, map[string]string{"sub/x.log": "x"})
+	file := filepath.Join(root, "sub", "x.log")
+
+	tests := []struct {
+		name  string
+		rules Rules
+		want  string
+	}{
+		{"excluído pelo nome", Rules{Exclude: []string{"*.log"}}, "regra: *.log"},
+		// Só o nome é comparado: padrões com pasta não casam
+		{"padrão com pasta", Rules{Exclude: []string{"sub/*.log"}}, ""},
+		{"incluído", Rules{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entries []Entry
+			if err := Walk(file, tt.rules, time.Now(), func(e Entry) { entries = append(entries, e) }); err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 || entries[0].Path != file {
+				t.Fatalf("entradas = %+v", entries)
+			}
+			if entries[0].Reason != tt.want {
+				t.Errorf("motivo = %q, esperado %q", entries[0].Reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkMissingRoot(t *testing.T) {
+	err := Walk(filepath.Join(t.TempDir(), "nada"), Rules{}, time.Now(), func(Entry) {})
+	if err == nil {
+		t.Error("esperado erro para raiz inexistente")
+	}
+}
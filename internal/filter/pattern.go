@@ -0,0 +1,154 @@
+// filter/pattern.go
+package filter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pattern é uma linha no formato do .gitignore: "*.log", "build/",
+// "/raiz-apenas", "docs/**/*.md" ou "!reincluir"
+type pattern struct {
+	re       *regexp.Regexp
+	negate   bool
+	dirOnly  bool
+	anchored bool
+	// source descreve a origem para o motivo da exclusão (ex: ".gitignore: *.log")
+	source string
+}
+
+// parsePattern interpreta uma linha; ok é falso para linhas vazias e comentários
+func parsePattern(line, origin string) (pattern, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return pattern{}, false
+	}
+
+	p := pattern{source: origin + ": " + line}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`)
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	// Com uma barra no início ou no meio, vale só a partir da pasta base
+	if strings.Contains(line, "/") {
+		p.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return pattern{}, false
+	}
+
+	re, err := regexp.Compile("^" + globRegexp(line) + "$")
+	if err != nil {
+		return pattern{}, false
+	}
+	p.re = re
+	return p, true
+}
+
+// match testa rel (relativo à pasta base, separado por "/")
+func (p pattern) match(rel string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	if p.anchored {
+		return p.re.MatchString(rel)
+	}
+	return p.re.MatchString(path.Base(rel))
+}
+
+// globRegexp traduz o glob para expressão regular: "*" e "?" não cruzam
+// pastas e "**" cruza qualquer número delas
+func globRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			b.WriteString("(?:/.*)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// parsePatterns interpreta uma lista de linhas
+func parsePatterns(lines []string, origin string) []pattern {
+	var patterns []pattern
+	for _, line := range lines {
+		if p, ok := parsePattern(line, origin); ok {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// readPatternFile lê um arquivo de padrões; arquivo ausente não é erro
+func readPatternFile(file string) []pattern {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return parsePatterns(lines, filepath.Base(file))
+}
+
+// Glob é um padrão avulso no formato do .gitignore (sem "!"), para regras
+// que escolhem arquivos pelo nome ou caminho
+type Glob struct {
+	p pattern
+}
+
+// ParseGlob interpreta text
+func ParseGlob(text string) (Glob, error) {
+	p, ok := parsePattern(text, "")
+	if !ok || p.negate {
+		return Glob{}, fmt.Errorf("padrão inválido: %q", text)
+	}
+	return Glob{p: p}, nil
+}
+
+// Match testa o arquivo rel (relativo, separado por "/")
+func (g Glob) Match(rel string) bool {
+	return g.p.re != nil && g.p.match(rel, false)
+}
@@ -0,0 +1,147 @@
+// headers/headers.go
+//
+// Cabeçalhos dos objetos enviados: Content-Type detectado pela extensão ou
+// pelo conteúdo e regras do usuário por padrão de arquivo (ex: "*.html" com
+// Cache-Control: no-cache).
+package headers
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"s3nd-files/internal/envelope"
+	"s3nd-files/internal/filter"
+	"s3nd-files/internal/models"
+)
+
+// Tipo usado quando nem a extensão nem o conteúdo dizem nada
+const fallbackType = "application/octet-stream"
+
+// Bytes lidos do início do arquivo para reconhecer o tipo
+const sniffLength = 512
+
+// Rule aplica Headers aos arquivos que casam com Pattern. Campos vazios
+// mantêm o valor detectado ou o de regras anteriores.
+type Rule struct {
+	Pattern string               `json:"pattern"`
+	Headers models.ObjectHeaders `json:"headers"`
+}
+
+// Validate confere o padrão e os nomes dos metadados
+func (r Rule) Validate() error {
+	if _, err := filter.ParseGlob(r.Pattern); err != nil {
+		return err
+	}
+	for key := range r.Headers.Metadata {
+		if !validMetadataKey(key) {
+			return fmt.Errorf("nome de metadado inválido em %s: %q", r.Pattern, key)
+		}
+		// Reservados para a criptografia no cliente
+		if strings.HasPrefix(strings.ToLower(key), envelope.MetaAlgorithm) {
+			return fmt.Errorf("nome de metadado reservado em %s: %q", r.Pattern, key)
+		}
+	}
+	return nil
+}
+
+// validMetadataKey aceita os caracteres permitidos em nomes de cabeçalho HTTP
+func validMetadataKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, c := range key {
+		if c > 0x7e || c <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolver calcula os cabeçalhos de cada arquivo de um envio
+type Resolver struct {
+	rules []Rule
+	globs []filter.Glob
+}
+
+// NewResolver prepara rules; as regras posteriores prevalecem
+func NewResolver(rules []Rule) (*Resolver, error) {
+	r := &Resolver{rules: rules}
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		glob, _ := filter.ParseGlob(rule.Pattern)
+		r.globs = append(r.globs, glob)
+	}
+	return r, nil
+}
+
+// Headers retorna os cabeçalhos do arquivo file, cujo caminho relativo é
+// rel (separado por "/", como a chave de destino)
+func (r *Resolver) Headers(file, rel string) (models.ObjectHeaders, error) {
+	h := models.ObjectHeaders{}
+	for i, rule := range r.rules {
+		if !r.globs[i].Match(rel) {
+			continue
+		}
+		merge(&h, rule.Headers)
+	}
+	if h.ContentType == "" {
+		contentType, err := DetectContentType(file)
+		if err != nil {
+			return models.ObjectHeaders{}, err
+		}
+		h.ContentType = contentType
+	}
+	return h, nil
+}
+
+// merge copia para dst os campos preenchidos de src
+func merge(dst *models.ObjectHeaders, src models.ObjectHeaders) {
+	if src.ContentType != "" {
+		dst.ContentType = src.ContentType
+	}
+	if src.CacheControl != "" {
+		dst.CacheControl = src.CacheControl
+	}
+	if src.ContentEncoding != "" {
+		dst.ContentEncoding = src.ContentEncoding
+	}
+	if src.ContentDisposition != "" {
+		dst.ContentDisposition = src.ContentDisposition
+	}
+	for k, v := range src.Metadata {
+		if dst.Metadata == nil {
+			dst.Metadata = make(map[string]string)
+		}
+		dst.Metadata[k] = v
+	}
+}
+
+// DetectContentType usa a extensão e, sem ela, o início do conteúdo
+func DetectContentType(file string) (string, error) {
+	if t := mime.TypeByExtension(strings.ToLower(filepath.Ext(file))); t != "" {
+		return t, nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return "", fmt.Errorf("falha ao abrir %s: %w", file, err)
+	}
+	defer f.Close()
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("falha ao ler %s: %w", file, err)
+	}
+	if n == 0 {
+		return fallbackType, nil
+	}
+	return http.DetectContentType(head[:n]), nil
+}
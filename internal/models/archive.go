@@ -0,0 +1,54 @@
+// models/archive.go
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// UploadStorageClasses lista as classes aceitas no envio de objetos
+var UploadStorageClasses = append([]string{"STANDARD"}, TransitionStorageClasses...)
+
+// RestoreTiers são as opções de velocidade de restauração do Glacier
+var RestoreTiers = []string{"Standard", "Bulk", "Expedited"}
+
+// IsArchiveClass informa se objetos da classe precisam ser restaurados
+// antes de serem lidos (GLACIER_IR é de acesso imediato)
+func IsArchiveClass(class string) bool {
+	return class == "GLACIER" || class == "DEEP_ARCHIVE"
+}
+
+// RestoreState é a situação da cópia temporária de um objeto arquivado
+type RestoreState int
+
+const (
+	RestoreNone RestoreState = iota
+	RestoreInProgress
+	RestoreDone
+)
+
+// RestoreStatus é o estado de restauração de um objeto arquivado
+type RestoreStatus struct {
+	State RestoreState
+	// Expiry é quando a cópia restaurada será removida
+	Expiry time.Time
+}
+
+// ParseRestoreHeader interpreta o cabeçalho x-amz-restore, por exemplo
+// `ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"`
+func ParseRestoreHeader(header string) RestoreStatus {
+	if header == "" {
+		return RestoreStatus{}
+	}
+	if strings.Contains(header, `ongoing-request="true"`) {
+		return RestoreStatus{State: RestoreInProgress}
+	}
+
+	status := RestoreStatus{State: RestoreDone}
+	if _, rest, ok := strings.Cut(header, `expiry-date="`); ok {
+		if date, _, ok := strings.Cut(rest, `"`); ok {
+			status.Expiry, _ = time.Parse(time.RFC1123, date)
+		}
+	}
+	return status
+}
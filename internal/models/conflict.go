@@ -0,0 +1,59 @@
+// models/conflict.go
+package models
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// ConflictPolicy decide o que fazer quando a chave de destino de um envio
+// já existe
+type ConflictPolicy string
+
+const (
+	ConflictAsk       ConflictPolicy = "ask"
+	ConflictSkip      ConflictPolicy = "skip"
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictNewer sobrescreve se o arquivo local for mais novo ou tiver
+	// outro tamanho
+	ConflictNewer ConflictPolicy = "newer"
+	// ConflictRename envia com um sufixo numerado: "nome (1).ext"
+	ConflictRename ConflictPolicy = "rename"
+)
+
+// ConflictPolicies lista as políticas na ordem de exibição
+var ConflictPolicies = []ConflictPolicy{ConflictAsk, ConflictSkip, ConflictOverwrite, ConflictNewer, ConflictRename}
+
+// Label é o nome da política na interface
+func (p ConflictPolicy) Label() string {
+	switch p {
+	case ConflictSkip:
+		return "Pular"
+	case ConflictOverwrite:
+		return "Sobrescrever"
+	case ConflictNewer:
+		return "Sobrescrever se mais novo ou diferente"
+	case ConflictRename:
+		return "Renomear com sufixo"
+	}
+	return "Perguntar"
+}
+
+// ReplacesRemote informa se, pela política ConflictNewer, o arquivo local
+// (size, modTime) deve substituir remote
+func ReplacesRemote(size int64, modTime time.Time, remote Object) bool {
+	return size != remote.Size || modTime.After(remote.LastModified)
+}
+
+// NumberedKey acrescenta " (n)" ao nome de key, antes da extensão
+func NumberedKey(key string, n int) string {
+	dir, name := path.Split(key)
+	ext := path.Ext(name)
+	// Arquivos ocultos sem extensão (".env") ficam "nome (n)"
+	if ext == name {
+		ext = ""
+	}
+	return fmt.Sprintf("%s%s (%d)%s", dir, strings.TrimSuffix(name, ext), n, ext)
+}
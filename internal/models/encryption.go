@@ -0,0 +1,108 @@
+// models/encryption.go
+package models
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Modos de criptografia no servidor. SSENone deixa o bucket decidir.
+const (
+	SSENone = ""
+	SSES3   = "SSE-S3"
+	SSEKMS  = "SSE-KMS"
+	SSEC    = "SSE-C"
+)
+
+// EncryptionModes lista os modos na ordem exibida pela interface
+var EncryptionModes = []string{SSENone, SSES3, SSEKMS, SSEC}
+
+// Encryption descreve como os objetos são criptografados no servidor
+type Encryption struct {
+	Mode string
+	// KMSKeyID é opcional; vazio usa a chave aws/s3 gerenciada pela AWS
+	KMSKeyID string
+	// CustomerKey é a chave AES-256 do SSE-C (32 bytes)
+	CustomerKey []byte
+}
+
+// Validate verifica se o modo tem os dados de que precisa
+func (e Encryption) Validate() error {
+	switch e.Mode {
+	case SSENone, SSES3, SSEKMS:
+		return nil
+	case SSEC:
+		if len(e.CustomerKey) != 32 {
+			return fmt.Errorf("SSE-C exige uma chave de 32 bytes")
+		}
+		return nil
+	default:
+		return fmt.Errorf("modo de criptografia desconhecido: %q", e.Mode)
+	}
+}
+
+// LoadKeyFile lê uma chave AES-256 de um arquivo (SSE-C ou chave mestra
+// do cliente). A chave pode estar em binário (32 bytes), base64 ou hexadecimal.
+func LoadKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler chave: %w", err)
+	}
+	if len(data) == 32 {
+		return data, nil
+	}
+
+	text := strings.TrimSpace(string(data))
+	if key, err := base64.StdEncoding.DecodeString(text); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := hex.DecodeString(text); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	return nil, fmt.Errorf("a chave deve ter 32 bytes (binário, base64 ou hex)")
+}
+
+// UploadOptions são as opções de um envio
+type UploadOptions struct {
+	// Encryption substitui o padrão da conexão; nil usa o padrão
+	Encryption *Encryption
+	// ClientSide cifra o conteúdo no cliente antes de enviá-lo
+	ClientSide bool
+	// StorageClass vazio usa a classe padrão do bucket (STANDARD)
+	StorageClass string
+	Tags         map[string]string
+	// Retenção e legal hold aplicados ao objeto (bucket com Object Lock)
+	Retention Retention
+	LegalHold bool
+	// IfAbsent só grava se a chave não existir (If-None-Match: *); do
+	// contrário o envio falha com ErrObjectExists
+	IfAbsent bool
+	// Headers são os cabeçalhos do objeto (Content-Type, Cache-Control...)
+	Headers ObjectHeaders
+}
+
+// ObjectInfo são os metadados de um objeto lidos com HEAD
+type ObjectInfo struct {
+	Key          string
+	VersionID    string
+	Size         int64
+	LastModified time.Time
+	ContentType  string
+	// ContentEncoding é "gzip" em objetos gravados comprimidos
+	ContentEncoding    string
+	CacheControl       string
+	ContentDisposition string
+	ETag               string
+	StorageClass       string
+	Encryption         string
+	KMSKeyID           string
+	// ClientEncrypted indica conteúdo cifrado no cliente (envelope)
+	ClientEncrypted bool
+	Restore         RestoreStatus
+	// Metadata são os metadados do usuário (x-amz-meta-*)
+	Metadata map[string]string
+}
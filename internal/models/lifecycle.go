@@ -0,0 +1,139 @@
+// models/lifecycle.go
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Classes de armazenamento aceitas como destino de transições
+var TransitionStorageClasses = []string{
+	"STANDARD_IA",
+	"ONEZONE_IA",
+	"INTELLIGENT_TIERING",
+	"GLACIER_IR",
+	"GLACIER",
+	"DEEP_ARCHIVE",
+}
+
+// LifecycleTransition move objetos para outra classe após Days dias
+type LifecycleTransition struct {
+	Days         int32  `json:"days"`
+	StorageClass string `json:"storageClass"`
+}
+
+// LifecycleRule é uma regra de ciclo de vida de um bucket. Campos de dias
+// zerados significam "ação desativada".
+type LifecycleRule struct {
+	ID      string `json:"id"`
+	Prefix  string `json:"prefix"`
+	Enabled bool   `json:"enabled"`
+
+	ExpirationDays           int32                 `json:"expirationDays,omitempty"`
+	NoncurrentExpirationDays int32                 `json:"noncurrentExpirationDays,omitempty"`
+	Transitions              []LifecycleTransition `json:"transitions,omitempty"`
+	AbortMultipartDays       int32                 `json:"abortIncompleteMultipartDays,omitempty"`
+
+	// Unsupported descreve o que a regra usa e o editor não representa
+	// (filtro por tags ou tamanho, datas...). Essas regras são mostradas só
+	// para leitura e regravadas exatamente como vieram.
+	Unsupported string `json:"unsupported,omitempty"`
+	// Original é a regra como veio do backend; ao salvar, só os campos
+	// acima são alterados nela
+	Original any `json:"-"`
+}
+
+// KeepOriginals liga as regras editadas (ex: vindas do JSON) às regras
+// lidas do bucket, pelo ID, e recusa alterações em regras só de leitura
+func KeepOriginals(edited, loaded []LifecycleRule) error {
+	byID := make(map[string]LifecycleRule, len(loaded))
+	for _, r := range loaded {
+		byID[r.ID] = r
+	}
+	for i := range edited {
+		r := &edited[i]
+		prev, found := byID[r.ID]
+		if !found {
+			if r.Unsupported != "" {
+				return fmt.Errorf("regra %q: regras não suportadas só podem vir do bucket", r.ID)
+			}
+			continue
+		}
+		r.Original = prev.Original
+		if prev.Unsupported == "" {
+			continue
+		}
+		mine, theirs := *r, prev
+		mine.Original, theirs.Original = nil, nil
+		if !reflect.DeepEqual(mine, theirs) {
+			return fmt.Errorf("regra %q: %s; edite-a no console do provedor", r.ID, prev.Unsupported)
+		}
+	}
+	return nil
+}
+
+// ValidateLifecycleRules verifica as regras antes de enviá-las ao bucket
+func ValidateLifecycleRules(rules []LifecycleRule) error {
+	ids := make(map[string]bool)
+
+	for i, r := range rules {
+		name := fmt.Sprintf("regra %d", i+1)
+		if r.ID != "" {
+			name = fmt.Sprintf("regra %q", r.ID)
+		}
+
+		if strings.TrimSpace(r.ID) == "" {
+			return fmt.Errorf("%s: id é obrigatório", name)
+		}
+		if len(r.ID) > 255 {
+			return fmt.Errorf("%s: id deve ter no máximo 255 caracteres", name)
+		}
+		if ids[r.ID] {
+			return fmt.Errorf("%s: id duplicado", name)
+		}
+		ids[r.ID] = true
+
+		// Regravada como veio do bucket
+		if r.Unsupported != "" {
+			continue
+		}
+
+		if r.ExpirationDays < 0 || r.NoncurrentExpirationDays < 0 || r.AbortMultipartDays < 0 {
+			return fmt.Errorf("%s: dias não podem ser negativos", name)
+		}
+		if r.ExpirationDays == 0 && r.NoncurrentExpirationDays == 0 &&
+			r.AbortMultipartDays == 0 && len(r.Transitions) == 0 {
+			return fmt.Errorf("%s: defina ao menos uma ação", name)
+		}
+
+		classes := make(map[string]bool)
+		for _, t := range r.Transitions {
+			if t.Days <= 0 {
+				return fmt.Errorf("%s: transição para %s precisa de dias > 0", name, t.StorageClass)
+			}
+			if !isTransitionStorageClass(t.StorageClass) {
+				return fmt.Errorf("%s: classe de armazenamento inválida: %q", name, t.StorageClass)
+			}
+			if classes[t.StorageClass] {
+				return fmt.Errorf("%s: transição duplicada para %s", name, t.StorageClass)
+			}
+			classes[t.StorageClass] = true
+
+			if r.ExpirationDays > 0 && t.Days >= r.ExpirationDays {
+				return fmt.Errorf("%s: transição para %s deve ocorrer antes da expiração", name, t.StorageClass)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isTransitionStorageClass(class string) bool {
+	for _, c := range TransitionStorageClasses {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+// models/lock.go
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Modos de retenção do Object Lock
+const (
+	LockGovernance = "GOVERNANCE"
+	LockCompliance = "COMPLIANCE"
+)
+
+// LockModes lista os modos na ordem exibida pela interface
+var LockModes = []string{LockGovernance, LockCompliance}
+
+// Retention é a retenção de um objeto; Mode vazio significa sem retenção
+type Retention struct {
+	Mode        string
+	RetainUntil time.Time
+}
+
+// Active informa se a retenção ainda impede alterar ou excluir o objeto
+func (r Retention) Active(now time.Time) bool {
+	return r.Mode != "" && r.RetainUntil.After(now)
+}
+
+// Validate verifica o modo e se a data está no futuro
+func (r Retention) Validate(now time.Time) error {
+	switch r.Mode {
+	case "":
+		return nil
+	case LockGovernance, LockCompliance:
+	default:
+		return fmt.Errorf("modo de retenção inválido: %q", r.Mode)
+	}
+	if !r.RetainUntil.After(now) {
+		return fmt.Errorf("a data de retenção deve estar no futuro")
+	}
+	return nil
+}
+
+// ObjectLock é o estado de Object Lock de um objeto
+type ObjectLock struct {
+	Retention Retention
+	LegalHold bool
+}
+
+// RetentionChange verifica se current pode virar next. Encurtar ou remover
+// uma retenção GOVERNANCE ativa exige bypass; COMPLIANCE ativa só pode ser
+// estendida.
+func RetentionChange(current, next Retention, now time.Time) (needsBypass bool, err error) {
+	if err := next.Validate(now); err != nil {
+		return false, err
+	}
+	if !current.Active(now) {
+		return false, nil
+	}
+
+	weaker := next.Mode == "" ||
+		next.RetainUntil.Before(current.RetainUntil) ||
+		(current.Mode == LockCompliance && next.Mode != LockCompliance)
+
+	switch {
+	case !weaker:
+		return false, nil
+	case current.Mode == LockCompliance:
+		return false, fmt.Errorf("retenção COMPLIANCE até %s só pode ser estendida",
+			current.RetainUntil.Local().Format("2006-01-02 15:04"))
+	default:
+		return true, nil
+	}
+}
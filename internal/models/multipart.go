@@ -0,0 +1,43 @@
+// models/multipart.go
+package models
+
+import "time"
+
+// MultipartUpload é um envio multipart iniciado e ainda não concluído
+type MultipartUpload struct {
+	Key          string
+	UploadID     string
+	Initiated    time.Time
+	StorageClass string
+	// Parts e Size somam as partes já enviadas
+	Parts int
+	Size  int64
+	// Own indica um envio iniciado por este app (registrado para retomada)
+	Own bool
+}
+
+// OlderThan informa se o envio foi iniciado há mais de days dias
+func (u MultipartUpload) OlderThan(days int, now time.Time) bool {
+	return u.Initiated.Before(now.AddDate(0, 0, -days))
+}
+
+// PendingUpload é o registro local de um envio multipart em andamento,
+// usado para retomá-lo se o app for fechado ou a conexão cair
+type PendingUpload struct {
+	Bucket   string `json:"bucket"`
+	Key      string `json:"key"`
+	UploadID string `json:"uploadId"`
+	// Path, Size e ModTime identificam o arquivo local; Path vazio indica
+	// um envio que não pode ser retomado (ex: conteúdo cifrado no cliente)
+	Path    string    `json:"path,omitempty"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	// Options resume as opções do envio (tipo, metadados, criptografia,
+	// tags, classe, Object Lock); só se retoma com as mesmas opções
+	Options string `json:"options,omitempty"`
+}
+
+// Matches informa se o registro corresponde ao mesmo arquivo local, sem alterações
+func (p PendingUpload) Matches(path string, size int64, modTime time.Time) bool {
+	return p.Path != "" && p.Path == path && p.Size == size && p.ModTime.Equal(modTime)
+}
@@ -0,0 +1,191 @@
+// models/policy.go
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Campos aceitos em um statement de política de bucket
+var policyStatementKeys = map[string]bool{
+	"Sid": true, "Effect": true,
+	"Principal": true, "NotPrincipal": true,
+	"Action": true, "NotAction": true,
+	"Resource": true, "NotResource": true,
+	"Condition": true,
+}
+
+// Forma de um ARN em qualquer partição (aws, aws-cn, aws-us-gov...):
+// arn:partição:serviço:região:conta:recurso
+var policyARN = regexp.MustCompile(`^arn:aws(-[a-z]+)*:[a-z0-9-]+:[^:]*:[^:]*:.+$`)
+
+// Forma de uma ação: serviço:Ação, com curingas (s3:Get*, s3-object-lambda:*)
+var policyAction = regexp.MustCompile(`^[a-z0-9-]+:[A-Za-z0-9*?]+$`)
+
+// ValidateBucketPolicy verifica a sintaxe JSON e a estrutura básica de uma
+// política de bucket. O S3 ainda faz a validação final ao salvar.
+func ValidateBucketPolicy(policy string) error {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		return fmt.Errorf("JSON inválido: %w", err)
+	}
+
+	var version string
+	if err := json.Unmarshal(doc["Version"], &version); err != nil ||
+		(version != "2012-10-17" && version != "2008-10-17") {
+		return fmt.Errorf(`"Version" deve ser "2012-10-17"`)
+	}
+
+	raw, ok := doc["Statement"]
+	if !ok {
+		return fmt.Errorf(`"Statement" é obrigatório`)
+	}
+
+	// Statement pode ser um objeto ou uma lista de objetos
+	var statements []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &statements); err != nil {
+		var single map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return fmt.Errorf(`"Statement" deve ser um objeto ou uma lista`)
+		}
+		statements = append(statements, single)
+	}
+	if len(statements) == 0 {
+		return fmt.Errorf(`"Statement" não pode ser vazio`)
+	}
+
+	for i, st := range statements {
+		name := fmt.Sprintf("statement %d", i+1)
+
+		for key := range st {
+			if !policyStatementKeys[key] {
+				return fmt.Errorf("%s: campo desconhecido %q", name, key)
+			}
+		}
+
+		var effect string
+		if err := json.Unmarshal(st["Effect"], &effect); err != nil || (effect != "Allow" && effect != "Deny") {
+			return fmt.Errorf(`%s: "Effect" deve ser "Allow" ou "Deny"`, name)
+		}
+		if !hasOneOf(st, "Principal", "NotPrincipal") {
+			return fmt.Errorf(`%s: informe "Principal" ou "NotPrincipal"`, name)
+		}
+		if !hasOneOf(st, "Action", "NotAction") {
+			return fmt.Errorf(`%s: informe "Action" ou "NotAction"`, name)
+		}
+		if !hasOneOf(st, "Resource", "NotResource") {
+			return fmt.Errorf(`%s: informe "Resource" ou "NotResource"`, name)
+		}
+
+		for _, key := range []string{"Action", "NotAction", "Resource", "NotResource"} {
+			values, err := stringOrList(st[key])
+			if err != nil {
+				return fmt.Errorf("%s: %q deve ser texto ou lista de textos", name, key)
+			}
+			for _, v := range values {
+				if strings.HasSuffix(key, "Resource") && v != "*" && !policyARN.MatchString(v) {
+					return fmt.Errorf("%s: recurso inválido %q (use arn:<partição>:s3:::bucket/...)", name, v)
+				}
+				if strings.HasSuffix(key, "Action") && v != "*" && !policyAction.MatchString(v) {
+					return fmt.Errorf("%s: ação inválida %q (use serviço:Ação, ex: s3:GetObject)", name, v)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// FormatJSON reindenta um documento JSON para comparação e exibição
+func FormatJSON(text string) string {
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(strings.TrimSpace(text)), "", "  "); err != nil {
+		return text
+	}
+	return out.String()
+}
+
+// hasOneOf verifica se exatamente uma das chaves está presente
+func hasOneOf(m map[string]json.RawMessage, a, b string) bool {
+	_, hasA := m[a]
+	_, hasB := m[b]
+	return hasA != hasB
+}
+
+// stringOrList aceita "x" ou ["x", "y"]; ausente retorna nil
+func stringOrList(raw json.RawMessage) ([]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list, nil
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err != nil {
+		return nil, err
+	}
+	return []string{single}, nil
+}
+
+// CORSRule é uma regra CORS do bucket, no mesmo formato JSON usado pelo
+// console da AWS
+type CORSRule struct {
+	ID             string   `json:"ID,omitempty"`
+	AllowedOrigins []string `json:"AllowedOrigins"`
+	AllowedMethods []string `json:"AllowedMethods"`
+	AllowedHeaders []string `json:"AllowedHeaders,omitempty"`
+	ExposeHeaders  []string `json:"ExposeHeaders,omitempty"`
+	MaxAgeSeconds  int32    `json:"MaxAgeSeconds,omitempty"`
+}
+
+var corsMethods = map[string]bool{
+	"GET": true, "PUT": true, "POST": true, "DELETE": true, "HEAD": true,
+}
+
+// ValidateCORSRules verifica as regras CORS antes de enviá-las ao bucket
+func ValidateCORSRules(rules []CORSRule) error {
+	if len(rules) > 100 {
+		return fmt.Errorf("no máximo 100 regras CORS são permitidas")
+	}
+
+	for i, r := range rules {
+		name := fmt.Sprintf("regra %d", i+1)
+		if r.ID != "" {
+			name = fmt.Sprintf("regra %q", r.ID)
+		}
+
+		if len(r.AllowedOrigins) == 0 {
+			return fmt.Errorf(`%s: "AllowedOrigins" é obrigatório`, name)
+		}
+		for _, o := range r.AllowedOrigins {
+			if strings.Count(o, "*") > 1 {
+				return fmt.Errorf("%s: origem %q pode ter no máximo um '*'", name, o)
+			}
+		}
+
+		if len(r.AllowedMethods) == 0 {
+			return fmt.Errorf(`%s: "AllowedMethods" é obrigatório`, name)
+		}
+		for _, m := range r.AllowedMethods {
+			if !corsMethods[m] {
+				return fmt.Errorf("%s: método inválido %q (use GET, PUT, POST, DELETE ou HEAD)", name, m)
+			}
+		}
+
+		for _, h := range r.AllowedHeaders {
+			if strings.Count(h, "*") > 1 {
+				return fmt.Errorf("%s: cabeçalho %q pode ter no máximo um '*'", name, h)
+			}
+		}
+
+		if r.MaxAgeSeconds < 0 {
+			return fmt.Errorf(`%s: "MaxAgeSeconds" não pode ser negativo`, name)
+		}
+	}
+
+	return nil
+}
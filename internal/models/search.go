@@ -0,0 +1,96 @@
+// models/search.go
+package models
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SearchQuery descreve os filtros de uma busca recursiva de objetos.
+// Campos zerados não filtram nada.
+type SearchQuery struct {
+	// Padrão glob (ex: *.csv) ou expressão regular, conforme Regex.
+	// Um glob sem "/" é comparado só com o nome do arquivo; com "/" é
+	// comparado com a chave relativa a Prefix.
+	Pattern string
+	Regex   bool
+	// Prefixo onde a busca começa
+	Prefix string
+
+	MinSize int64
+	MaxSize int64 // 0 = sem limite
+
+	ModifiedAfter  time.Time
+	ModifiedBefore time.Time
+
+	StorageClass string
+
+	// Valor vazio aceita qualquer valor para a chave
+	Tags map[string]string
+}
+
+// Matcher compila a consulta e devolve uma função que testa os filtros
+// de chave, tamanho, data e classe de armazenamento. Tags são verificadas
+// à parte com MatchTags, pois exigem uma requisição por objeto.
+func (q SearchQuery) Matcher() (func(Object) bool, error) {
+	var matchKey func(string) bool
+
+	switch {
+	case q.Pattern == "":
+		matchKey = func(string) bool { return true }
+	case q.Regex:
+		re, err := regexp.Compile(q.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("expressão regular inválida: %w", err)
+		}
+		matchKey = re.MatchString
+	default:
+		if _, err := path.Match(q.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("padrão inválido: %w", err)
+		}
+		fullKey := strings.Contains(q.Pattern, "/")
+		matchKey = func(key string) bool {
+			if fullKey {
+				// O prefixo pode vir sem a barra final
+				key = strings.TrimPrefix(strings.TrimPrefix(key, q.Prefix), "/")
+			} else {
+				key = path.Base(key)
+			}
+			ok, _ := path.Match(q.Pattern, key)
+			return ok
+		}
+	}
+
+	return func(obj Object) bool {
+		if obj.Size < q.MinSize {
+			return false
+		}
+		if q.MaxSize > 0 && obj.Size > q.MaxSize {
+			return false
+		}
+		if !q.ModifiedAfter.IsZero() && obj.LastModified.Before(q.ModifiedAfter) {
+			return false
+		}
+		if !q.ModifiedBefore.IsZero() && !obj.LastModified.Before(q.ModifiedBefore) {
+			return false
+		}
+		if q.StorageClass != "" && !strings.EqualFold(obj.StorageClass, q.StorageClass) {
+			return false
+		}
+		return matchKey(obj.Key)
+	}, nil
+}
+
+// MatchTags verifica se as tags do objeto satisfazem o filtro de tags
+func (q SearchQuery) MatchTags(tags map[string]string) bool {
+	for k, v := range q.Tags {
+		got, ok := tags[k]
+		if !ok || (v != "" && got != v) {
+			return false
+		}
+	}
+	return true
+}
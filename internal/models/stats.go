@@ -0,0 +1,101 @@
+// models/stats.go
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Object é um objeto encontrado em uma listagem recursiva
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	StorageClass string
+	ETag         string
+}
+
+// Usage agrega bytes e quantidade de objetos
+type Usage struct {
+	Bytes int64
+	Count int64
+}
+
+// PrefixStats é o resultado do cálculo de tamanho de um prefixo (ou bucket)
+type PrefixStats struct {
+	Bucket         string
+	Prefix         string
+	Total          Usage
+	ByStorageClass map[string]Usage
+	// Chave é o nome da subpasta imediata ("" para arquivos direto no prefixo)
+	BySubfolder map[string]Usage
+	// Maiores objetos, do maior para o menor
+	Largest []Object
+
+	largestLimit int
+}
+
+// NewPrefixStats cria estatísticas vazias que guardam os largestLimit maiores objetos
+func NewPrefixStats(bucket, prefix string, largestLimit int) *PrefixStats {
+	return &PrefixStats{
+		Bucket:         bucket,
+		Prefix:         prefix,
+		ByStorageClass: make(map[string]Usage),
+		BySubfolder:    make(map[string]Usage),
+		largestLimit:   largestLimit,
+	}
+}
+
+// Add contabiliza um objeto
+func (s *PrefixStats) Add(obj Object) {
+	s.Total.Bytes += obj.Size
+	s.Total.Count++
+
+	class := s.ByStorageClass[obj.StorageClass]
+	class.Bytes += obj.Size
+	class.Count++
+	s.ByStorageClass[obj.StorageClass] = class
+
+	sub := ""
+	rest := strings.TrimPrefix(obj.Key, s.Prefix)
+	if i := strings.Index(rest, "/"); i >= 0 {
+		sub = rest[:i+1]
+	}
+	folder := s.BySubfolder[sub]
+	folder.Bytes += obj.Size
+	folder.Count++
+	s.BySubfolder[sub] = folder
+
+	if s.largestLimit <= 0 {
+		return
+	}
+	if len(s.Largest) == s.largestLimit && obj.Size <= s.Largest[len(s.Largest)-1].Size {
+		return
+	}
+	i := sort.Search(len(s.Largest), func(i int) bool {
+		return s.Largest[i].Size < obj.Size
+	})
+	s.Largest = append(s.Largest, Object{})
+	copy(s.Largest[i+1:], s.Largest[i:])
+	s.Largest[i] = obj
+	if len(s.Largest) > s.largestLimit {
+		s.Largest = s.Largest[:s.largestLimit]
+	}
+}
+
+// Snapshot devolve uma cópia independente, segura para exibir enquanto
+// o cálculo continua
+func (s *PrefixStats) Snapshot() PrefixStats {
+	out := *s
+	out.ByStorageClass = make(map[string]Usage, len(s.ByStorageClass))
+	for k, v := range s.ByStorageClass {
+		out.ByStorageClass[k] = v
+	}
+	out.BySubfolder = make(map[string]Usage, len(s.BySubfolder))
+	for k, v := range s.BySubfolder {
+		out.BySubfolder[k] = v
+	}
+	out.Largest = append([]Object(nil), s.Largest...)
+	return out
+}
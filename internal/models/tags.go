@@ -0,0 +1,78 @@
+// models/tags.go
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limites de tags de objetos do S3
+const (
+	MaxObjectTags  = 10
+	maxTagKeyLen   = 128
+	maxTagValueLen = 256
+)
+
+// ValidateTags verifica os limites de quantidade e tamanho das tags
+func ValidateTags(tags map[string]string) error {
+	if len(tags) > MaxObjectTags {
+		return fmt.Errorf("no máximo %d tags por objeto (informadas: %d)", MaxObjectTags, len(tags))
+	}
+	for k, v := range tags {
+		if strings.TrimSpace(k) == "" {
+			return fmt.Errorf("tag com chave vazia")
+		}
+		if strings.HasPrefix(strings.ToLower(k), "aws:") {
+			return fmt.Errorf("tag %q: o prefixo aws: é reservado", k)
+		}
+		if utf8.RuneCountInString(k) > maxTagKeyLen {
+			return fmt.Errorf("tag %q: chave com mais de %d caracteres", k, maxTagKeyLen)
+		}
+		if utf8.RuneCountInString(v) > maxTagValueLen {
+			return fmt.Errorf("tag %q: valor com mais de %d caracteres", k, maxTagValueLen)
+		}
+	}
+	return nil
+}
+
+// FormatTags escreve as tags como "chave=valor" em ordem alfabética
+func FormatTags(tags map[string]string, sep string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+tags[k])
+	}
+	return strings.Join(parts, sep)
+}
+
+// TagEdit é uma alteração de tags aplicada a vários objetos
+type TagEdit struct {
+	Set    map[string]string
+	Remove []string
+	// Replace descarta as tags atuais antes de aplicar Set
+	Replace bool
+}
+
+// Apply retorna as tags resultantes da edição sobre current
+func (e TagEdit) Apply(current map[string]string) map[string]string {
+	out := make(map[string]string, len(current)+len(e.Set))
+	if !e.Replace {
+		for k, v := range current {
+			out[k] = v
+		}
+	}
+	for _, k := range e.Remove {
+		delete(out, k)
+	}
+	for k, v := range e.Set {
+		out[k] = v
+	}
+	return out
+}
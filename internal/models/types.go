@@ -1,6 +1,11 @@
 // s3/types.go (crie este arquivo)
 package models
 
+import (
+	"sort"
+	"strings"
+)
+
 type ItemType int
 
 const (
@@ -13,4 +18,23 @@ type Item struct {
 	Name   string
 	Type   ItemType
 	Prefix string
-}
\ No newline at end of file
+
+	// Só para arquivos, quando o backend informa
+	StorageClass string
+	Restore      RestoreStatus
+}
+
+// SortItems ordena pastas primeiro e depois por nome (sem diferenciar maiúsculas)
+func SortItems(items []Item) {
+	sort.Slice(items, func(i, j int) bool {
+		// Pastas primeiro
+		if items[i].Type == Folder && items[j].Type != Folder {
+			return true
+		}
+		if items[i].Type != Folder && items[j].Type == Folder {
+			return false
+		}
+		// Depois ordenar por nome
+		return strings.ToLower(items[i].Name) < strings.ToLower(items[j].Name)
+	})
+}
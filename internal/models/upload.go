@@ -0,0 +1,20 @@
+// models/upload.go
+package models
+
+import "errors"
+
+// ErrObjectExists indica que o envio condicional encontrou a chave ocupada
+var ErrObjectExists = errors.New("já existe um objeto com esta chave")
+
+// ErrConflict indica que o objeto mudou desde que foi lido (gravação condicional)
+var ErrConflict = errors.New("o objeto foi alterado desde que foi aberto")
+
+// ObjectHeaders são os cabeçalhos HTTP e metadados gravados com o objeto;
+// campos vazios não são enviados
+type ObjectHeaders struct {
+	ContentType        string            `json:"contentType,omitempty"`
+	CacheControl       string            `json:"cacheControl,omitempty"`
+	ContentEncoding    string            `json:"contentEncoding,omitempty"`
+	ContentDisposition string            `json:"contentDisposition,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+}
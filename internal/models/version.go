@@ -0,0 +1,95 @@
+// models/version.go
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// ObjectVersion é uma versão de um objeto em um bucket versionado
+type ObjectVersion struct {
+	Key            string
+	VersionID      string
+	IsLatest       bool
+	IsDeleteMarker bool
+	Size           int64
+	LastModified   time.Time
+	StorageClass   string
+	ETag           string
+}
+
+// RecoveryCandidate é uma chave que pode voltar a uma versão anterior
+type RecoveryCandidate struct {
+	Key string
+	// Versão que deve voltar a ser a atual
+	Target ObjectVersion
+	// Versões e marcadores posteriores a Target, do mais recente ao mais antigo
+	Newer []ObjectVersion
+}
+
+// OnlyDeleteMarkers indica que basta remover os marcadores posteriores
+// para Target voltar a ser a versão atual, sem copiar dados
+func (c RecoveryCandidate) OnlyDeleteMarkers() bool {
+	for _, v := range c.Newer {
+		if !v.IsDeleteMarker {
+			return false
+		}
+	}
+	return true
+}
+
+// PlanRecovery decide o que recuperar a partir de todas as versões de um
+// prefixo. Com asOf zerado, retorna as chaves cuja versão atual é um
+// marcador de exclusão, apontando para a última versão com conteúdo. Com
+// asOf preenchido, retorna as chaves que existiam naquele instante e cujo
+// estado atual é diferente, apontando para a versão vigente em asOf.
+// Chaves que não existiam em asOf não são tocadas.
+func PlanRecovery(versions []ObjectVersion, asOf time.Time) []RecoveryCandidate {
+	byKey := make(map[string][]ObjectVersion)
+	for _, v := range versions {
+		byKey[v.Key] = append(byKey[v.Key], v)
+	}
+
+	var out []RecoveryCandidate
+	for key, history := range byKey {
+		// Mais recente primeiro; IsLatest desempata datas iguais
+		sort.SliceStable(history, func(i, j int) bool {
+			if !history[i].LastModified.Equal(history[j].LastModified) {
+				return history[i].LastModified.After(history[j].LastModified)
+			}
+			return history[i].IsLatest && !history[j].IsLatest
+		})
+
+		target := -1
+		for i, v := range history {
+			if asOf.IsZero() {
+				if i == 0 && !v.IsDeleteMarker {
+					break // Não está excluída
+				}
+				if !v.IsDeleteMarker {
+					target = i
+					break
+				}
+				continue
+			}
+			if !v.LastModified.After(asOf) {
+				if !v.IsDeleteMarker {
+					target = i
+				}
+				break
+			}
+		}
+
+		if target <= 0 {
+			continue
+		}
+		out = append(out, RecoveryCandidate{
+			Key:    key,
+			Target: history[target],
+			Newer:  append([]ObjectVersion(nil), history[:target]...),
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
@@ -0,0 +1,153 @@
+// naming/exif.go
+package naming
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Tags EXIF de data, da mais à menos precisa
+const (
+	tagExifIFD           = 0x8769
+	tagDateTime          = 0x0132
+	tagDateTimeOriginal  = 0x9003
+	tagDateTimeDigitized = 0x9004
+)
+
+// Quanto ler do início de arquivos TIFF (e RAWs baseados nele) para achar
+// as tags
+const tiffReadLimit = 1 << 20
+
+// CaptureTime lê a data de captura gravada em fotos JPEG ou TIFF (incluindo
+// RAWs como DNG, CR2, NEF e ARW). ok é falso quando o arquivo não tem EXIF.
+func CaptureTime(file string) (time.Time, bool) {
+	f, err := os.Open(file)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	head, err := r.Peek(4)
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch {
+	case head[0] == 0xFF && head[1] == 0xD8:
+		tiff, ok := jpegExif(r)
+		if !ok {
+			return time.Time{}, false
+		}
+		return tiffTime(tiff)
+	case bytes.Equal(head, []byte("II*\x00")) || bytes.Equal(head, []byte("MM\x00*")):
+		tiff, _ := io.ReadAll(io.LimitReader(r, tiffReadLimit))
+		return tiffTime(tiff)
+	}
+	return time.Time{}, false
+}
+
+// jpegExif percorre os segmentos do JPEG até o APP1 com o bloco EXIF
+func jpegExif(r *bufio.Reader) ([]byte, bool) {
+	r.Discard(2)
+	for {
+		var marker [4]byte
+		if _, err := io.ReadFull(r, marker[:]); err != nil || marker[0] != 0xFF {
+			return nil, false
+		}
+		// Início dos dados da imagem: não há mais metadados
+		if marker[1] == 0xDA || marker[1] == 0xD9 {
+			return nil, false
+		}
+		size := int(binary.BigEndian.Uint16(marker[2:])) - 2
+		if size < 0 {
+			return nil, false
+		}
+		if marker[1] != 0xE1 {
+			if _, err := r.Discard(size); err != nil {
+				return nil, false
+			}
+			continue
+		}
+		segment := make([]byte, size)
+		if _, err := io.ReadFull(r, segment); err != nil {
+			return nil, false
+		}
+		if tiff, ok := bytes.CutPrefix(segment, []byte("Exif\x00\x00")); ok {
+			return tiff, true
+		}
+	}
+}
+
+// tiffTime procura as datas no IFD0 e no IFD EXIF
+func tiffTime(b []byte) (time.Time, bool) {
+	if len(b) < 8 {
+		return time.Time{}, false
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if b[0] == 'M' {
+		order = binary.BigEndian
+	}
+
+	ifd0 := readIFD(b, order, order.Uint32(b[4:]))
+	if exif, ok := ifd0[tagExifIFD]; ok {
+		tags := readIFD(b, order, order.Uint32(exif.value))
+		for _, tag := range []uint16{tagDateTimeOriginal, tagDateTimeDigitized} {
+			if t, ok := parseExifTime(b, order, tags[tag]); ok {
+				return t, true
+			}
+		}
+	}
+	return parseExifTime(b, order, ifd0[tagDateTime])
+}
+
+// ifdEntry é uma entrada de diretório TIFF; value guarda os 4 bytes do
+// valor (ou do deslocamento, para valores maiores)
+type ifdEntry struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+func readIFD(b []byte, order binary.ByteOrder, offset uint32) map[uint16]ifdEntry {
+	entries := make(map[uint16]ifdEntry)
+	if int64(offset)+2 > int64(len(b)) {
+		return entries
+	}
+	n := int(order.Uint16(b[offset:]))
+	for i := 0; i < n; i++ {
+		start := int(offset) + 2 + i*12
+		if start+12 > len(b) {
+			break
+		}
+		e := b[start : start+12]
+		entries[order.Uint16(e)] = ifdEntry{typ: order.Uint16(e[2:]), count: order.Uint32(e[4:]), value: e[8:12]}
+	}
+	return entries
+}
+
+// parseExifTime lê uma data ASCII no formato "2006:01:02 15:04:05"
+func parseExifTime(b []byte, order binary.ByteOrder, e ifdEntry) (time.Time, bool) {
+	const typeASCII = 2
+	if e.typ != typeASCII || e.count == 0 {
+		return time.Time{}, false
+	}
+	raw := e.value
+	if e.count > 4 {
+		offset := order.Uint32(e.value)
+		if int64(offset)+int64(e.count) > int64(len(b)) {
+			return time.Time{}, false
+		}
+		raw = b[offset : offset+e.count]
+	}
+	text := strings.TrimRight(string(raw), "\x00 ")
+	t, err := time.ParseInLocation("2006:01:02 15:04:05", text, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
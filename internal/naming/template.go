@@ -0,0 +1,239 @@
+// naming/template.go
+//
+// Modelos de chave para envios, como "raw/{yyyy}/{mm}/{dd}/{path}".
+package naming
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTemplate envia só o nome do arquivo, como antes dos modelos
+const DefaultTemplate = "{name}"
+
+// TreeTemplate mantém a estrutura relativa das pastas adicionadas
+const TreeTemplate = "{path}"
+
+// Help descreve os marcadores aceitos
+const Help = "{yyyy} {yy} {mm} {dd} {hh} {mi} {ss}: data e hora do envio\n" +
+	"{exif.yyyy}, {exif.mm}...: data da foto (EXIF; sem ela, a de modificação)\n" +
+	"{mtime.yyyy}, {mtime.mm}...: data de modificação do arquivo\n" +
+	"{path}: caminho relativo  {dir}: pasta relativa  {name}: nome\n" +
+	"{stem}: nome sem extensão  {ext}: extensão sem ponto\n" +
+	"{hash} ou {hash:8}: SHA-256 do conteúdo  {uuid}: identificador aleatório\n" +
+	"{host}: nome deste computador"
+
+// Source é um arquivo a enviar
+type Source struct {
+	Path string
+	// Rel é o caminho relativo, separado por "/" (ex: fotos/2024/a.jpg)
+	Rel     string
+	ModTime time.Time
+}
+
+// Env guarda o que vale para todos os arquivos de um envio
+type Env struct {
+	Now  time.Time
+	Host string
+}
+
+// NewEnv usa a hora atual e o nome deste computador
+func NewEnv() Env {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "desconhecido"
+	}
+	return Env{Now: time.Now(), Host: host}
+}
+
+// Partes de data aceitas depois de "", "exif." ou "mtime."
+var dateParts = map[string]string{
+	"yyyy": "2006",
+	"yy":   "06",
+	"mm":   "01",
+	"dd":   "02",
+	"hh":   "15",
+	"mi":   "04",
+	"ss":   "05",
+}
+
+// part é um trecho literal ou um marcador
+type part struct {
+	literal string
+	name    string
+	arg     int
+}
+
+// Template é um modelo interpretado
+type Template struct {
+	text  string
+	parts []part
+	exif  bool
+	hash  bool
+}
+
+// Parse interpreta text e recusa marcadores desconhecidos
+func Parse(text string) (*Template, error) {
+	t := &Template{text: text}
+	rest := text
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			t.parts = append(t.parts, part{literal: rest})
+			break
+		}
+		if open > 0 {
+			t.parts = append(t.parts, part{literal: rest[:open]})
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("modelo inválido: \"{\" sem \"}\" em %q", text)
+		}
+		p, err := parsePlaceholder(rest[open+1 : open+end])
+		if err != nil {
+			return nil, err
+		}
+		t.parts = append(t.parts, p)
+		t.exif = t.exif || strings.HasPrefix(p.name, "exif.")
+		t.hash = t.hash || p.name == "hash"
+		rest = rest[open+end+1:]
+	}
+	if len(t.parts) == 0 {
+		return nil, fmt.Errorf("modelo vazio")
+	}
+	return t, nil
+}
+
+func parsePlaceholder(text string) (part, error) {
+	name, arg, hasArg := strings.Cut(text, ":")
+	p := part{name: name}
+	if hasArg {
+		n, err := strconv.Atoi(arg)
+		if name != "hash" || err != nil || n <= 0 {
+			return part{}, fmt.Errorf("marcador inválido: {%s}", text)
+		}
+		p.arg = n
+	}
+
+	date := strings.TrimPrefix(strings.TrimPrefix(name, "exif."), "mtime.")
+	if _, ok := dateParts[date]; ok {
+		return p, nil
+	}
+	switch name {
+	case "path", "dir", "name", "stem", "ext", "hash", "uuid", "host":
+		return p, nil
+	}
+	return part{}, fmt.Errorf("marcador desconhecido: {%s}", text)
+}
+
+// String retorna o texto do modelo
+func (t *Template) String() string { return t.text }
+
+// Key monta a chave de src, relativa à pasta de destino. Lê o arquivo só
+// quando o modelo usa {hash} ou datas EXIF.
+func (t *Template) Key(src Source, env Env) (string, error) {
+	rel := strings.TrimPrefix(path.Clean("/"+src.Rel), "/")
+	name := path.Base(rel)
+	ext := path.Ext(name)
+
+	var (
+		captured time.Time
+		digest   string
+	)
+	if t.exif {
+		captured = src.ModTime
+		if taken, ok := CaptureTime(src.Path); ok {
+			captured = taken
+		}
+	}
+	if t.hash {
+		var err error
+		if digest, err = fileHash(src.Path); err != nil {
+			return "", err
+		}
+	}
+
+	var b strings.Builder
+	for _, p := range t.parts {
+		switch {
+		case p.name == "":
+			b.WriteString(p.literal)
+		case p.name == "path":
+			b.WriteString(rel)
+		case p.name == "dir":
+			if dir := path.Dir(rel); dir != "." {
+				b.WriteString(dir)
+			}
+		case p.name == "name":
+			b.WriteString(name)
+		case p.name == "stem":
+			b.WriteString(strings.TrimSuffix(name, ext))
+		case p.name == "ext":
+			b.WriteString(strings.TrimPrefix(ext, "."))
+		case p.name == "hash":
+			if p.arg > 0 && p.arg < len(digest) {
+				b.WriteString(digest[:p.arg])
+			} else {
+				b.WriteString(digest)
+			}
+		case p.name == "uuid":
+			b.WriteString(newUUID())
+		case p.name == "host":
+			b.WriteString(env.Host)
+		case strings.HasPrefix(p.name, "exif."):
+			b.WriteString(captured.Format(dateParts[strings.TrimPrefix(p.name, "exif.")]))
+		case strings.HasPrefix(p.name, "mtime."):
+			b.WriteString(src.ModTime.Format(dateParts[strings.TrimPrefix(p.name, "mtime.")]))
+		default:
+			b.WriteString(env.Now.Format(dateParts[p.name]))
+		}
+	}
+
+	key := cleanKey(b.String())
+	if key == "" {
+		return "", fmt.Errorf("o modelo %q gerou uma chave vazia para %s", t.text, rel)
+	}
+	return key, nil
+}
+
+// cleanKey junta barras repetidas e tira as do início, que sobram de
+// marcadores vazios como {dir}
+func cleanKey(key string) string {
+	for strings.Contains(key, "//") {
+		key = strings.ReplaceAll(key, "//", "/")
+	}
+	return strings.TrimPrefix(key, "/")
+}
+
+// fileHash calcula o SHA-256 do conteúdo em hexadecimal
+func fileHash(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", fmt.Errorf("falha ao abrir %s: %w", file, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("falha ao ler %s: %w", file, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// newUUID gera um UUID versão 4
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	s := hex.EncodeToString(b[:])
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
@@ -0,0 +1,257 @@
+package naming
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"{path",
+		"{foo}",
+		"{exif.foo}",
+		"{hash:0}",
+		"{hash:x}",
+		"{yyyy:2}",
+	}
+	for _, text := range tests {
+		t.Run(text, func(t *testing.T) {
+			if _, err := Parse(text); err == nil {
+				t.Errorf("Parse(%q) deveria falhar", text)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(p, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestKey(t *testing.T) {
+	env := Env{Now: time.Date(2024, 3, 5, 14, 7, 9, 0, time.Local), Host: "estacao"}
+	mtime := time.Date(2022, 12, 31, 23, 59, 58, 0, time.Local)
+	// SHA-256 de "abc": ba7816bf...
+	plain := writeFile(t, "IMG_1.JPG", []byte("abc"))
+	photo := writeFile(t, "foto.jpg", jpegWithExif(tiffWithDates(binary.LittleEndian, "2021:01:01 00:00:00", "2023:05:06 07:08:09")))
+
+	src := Source{Path: plain, Rel: "fotos/2023/IMG_1.JPG", ModTime: mtime}
+	tests := []struct {
+		name     string
+		template string
+		src      Source
+		want     string
+		err      bool
+	}{
+		{"caminho", "{path}", src, "fotos/2023/IMG_1.JPG", false},
+		{"data do envio", "raw/{yyyy}/{mm}/{dd}/{name}", src, "raw/2024/03/05/IMG_1.JPG", false},
+		{"hora do envio", "{yy}{hh}{mi}{ss}", src, "24140709", false},
+		{"partes do nome", "{dir}/{stem}.{ext}", src, "fotos/2023/IMG_1.JPG", false},
+		{"data de modificação", "{mtime.yyyy}-{mtime.mm}/{name}", src, "2022-12/IMG_1.JPG", false},
+		{"exif ausente usa modificação", "{exif.yyyy}/{exif.mm}/{name}", src, "2022/12/IMG_1.JPG", false},
+		{"exif", "{exif.yyyy}/{exif.mm}/{exif.dd}/{name}",
+			Source{Path: photo, Rel: "foto.jpg", ModTime: mtime}, "2023/05/06/foto.jpg", false},
+		{"hash curto", "{hash:8}.{ext}", src, "ba7816bf.JPG", false},
+		{"hash completo", "{hash}", src, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", false},
+		{"hash maior que o resumo", "{hash:100}", src,
+			"ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", false},
+		{"host", "{host}/{name}", src, "estacao/IMG_1.JPG", false},
+		{"pasta vazia", "{dir}/{name}", Source{Path: plain, Rel: "a.txt", ModTime: mtime}, "a.txt", false},
+		{"barras repetidas", "//x//{name}", src, "x/IMG_1.JPG", false},
+		{"caminho com ..", "{path}", Source{Path: plain, Rel: "../../x/a.txt", ModTime: mtime}, "x/a.txt", false},
+		{"sem extensão", "{stem}-{ext}", Source{Path: plain, Rel: "LEIAME", ModTime: mtime}, "LEIAME-", false},
+		{"chave vazia", "{dir}", Source{Path: plain, Rel: "a.txt", ModTime: mtime}, "", true},
+		{"hash de arquivo ausente", "{hash}", Source{Path: filepath.Join(t.TempDir(), "nada"), Rel: "nada"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := tmpl.Key(tt.src, env)
+			if (err != nil) != tt.err {
+				t.Fatalf("erro = %v, esperado erro: %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("Key = %q, esperado %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// O modelo padrão repete a chave de antes dos modelos: só o nome do arquivo
+func TestDefaultTemplate(t *testing.T) {
+	tmpl, err := Parse(DefaultTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{"a.txt", "fotos/2023/IMG_1.JPG"} {
+		got, err := tmpl.Key(Source{Rel: rel}, Env{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := path.Base(rel); got != want {
+			t.Errorf("Key(%q) = %q, esperado %q", rel, got, want)
+		}
+	}
+}
+
+func TestKeyUUID(t *testing.T) {
+	tmpl, err := Parse("{uuid}/{name}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := Source{Rel: "a.txt"}
+	a, err := tmpl.Key(src, Env{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := tmpl.Key(src, Env{})
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}/a\.txt$`)
+	if !uuid.MatchString(a) {
+		t.Errorf("Key = %q, esperado um UUID v4", a)
+	}
+	if a == b {
+		t.Error("cada chave deve receber um UUID novo")
+	}
+}
+
+// tiffWithDates monta um TIFF mínimo com DateTime no IFD0 e, se original
+// não for vazio, DateTimeOriginal em um IFD EXIF
+func tiffWithDates(order binary.ByteOrder, dateTime, original string) []byte {
+	type entry struct {
+		tag, typ     uint16
+		count, value uint32
+	}
+	const typeASCII, typeLong = 2, 4
+
+	ifd0Off := uint32(8)
+	n0 := 0
+	if dateTime != "" {
+		n0++
+	}
+	if original != "" {
+		n0++
+	}
+	exifOff := ifd0Off + 2 + 12*uint32(n0) + 4
+	dataOff := exifOff
+	if original != "" {
+		dataOff += 2 + 12 + 4
+	}
+
+	var data []byte
+	addString := func(s string) (uint32, uint32) {
+		off := dataOff + uint32(len(data))
+		data = append(data, s...)
+		data = append(data, 0)
+		return off, uint32(len(s) + 1)
+	}
+
+	var ifd0, exif []entry
+	if dateTime != "" {
+		off, count := addString(dateTime)
+		ifd0 = append(ifd0, entry{tagDateTime, typeASCII, count, off})
+	}
+	if original != "" {
+		ifd0 = append(ifd0, entry{tagExifIFD, typeLong, 1, exifOff})
+		off, count := addString(original)
+		exif = append(exif, entry{tagDateTimeOriginal, typeASCII, count, off})
+	}
+
+	var b bytes.Buffer
+	if order == binary.LittleEndian {
+		b.WriteString("II*\x00")
+	} else {
+		b.WriteString("MM\x00*")
+	}
+	binary.Write(&b, order, ifd0Off)
+	writeIFD := func(entries []entry) {
+		binary.Write(&b, order, uint16(len(entries)))
+		for _, e := range entries {
+			binary.Write(&b, order, e)
+		}
+		binary.Write(&b, order, uint32(0))
+	}
+	writeIFD(ifd0)
+	if len(exif) > 0 {
+		writeIFD(exif)
+	}
+	b.Write(data)
+	return b.Bytes()
+}
+
+// jpegWithExif embrulha tiff em um JPEG (SOI, APP0, APP1 com EXIF, SOS)
+func jpegWithExif(tiff []byte) []byte {
+	var b bytes.Buffer
+	b.Write([]byte{0xFF, 0xD8})
+	app0 := []byte("JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00")
+	b.Write([]byte{0xFF, 0xE0})
+	binary.Write(&b, binary.BigEndian, uint16(len(app0)+2))
+	b.Write(app0)
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+	b.Write([]byte{0xFF, 0xE1})
+	binary.Write(&b, binary.BigEndian, uint16(len(app1)+2))
+	b.Write(app1)
+	b.Write([]byte{0xFF, 0xDA, 0x00, 0x02})
+	return b.Bytes()
+}
+
+func TestCaptureTime(t *testing.T) {
+	at := func(s string) time.Time {
+		tm, err := time.ParseInLocation("2006:01:02 15:04:05", s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want time.Time
+		ok   bool
+	}{
+		{"jpeg com DateTimeOriginal", jpegWithExif(tiffWithDates(binary.LittleEndian, "2020:01:01 00:00:00", "2023:05:06 07:08:09")),
+			at("2023:05:06 07:08:09"), true},
+		{"jpeg só com DateTime", jpegWithExif(tiffWithDates(binary.BigEndian, "2020:02:03 04:05:06", "")),
+			at("2020:02:03 04:05:06"), true},
+		{"tiff little-endian", tiffWithDates(binary.LittleEndian, "", "2019:07:08 09:10:11"),
+			at("2019:07:08 09:10:11"), true},
+		{"tiff big-endian", tiffWithDates(binary.BigEndian, "2018:12:24 18:00:00", ""),
+			at("2018:12:24 18:00:00"), true},
+		{"data inválida", tiffWithDates(binary.LittleEndian, "ontem", ""), time.Time{}, false},
+		{"tiff sem datas", tiffWithDates(binary.LittleEndian, "", ""), time.Time{}, false},
+		{"jpeg sem EXIF", []byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02}, time.Time{}, false},
+		{"jpeg truncado", jpegWithExif(tiffWithDates(binary.LittleEndian, "2020:01:01 00:00:00", ""))[:12], time.Time{}, false},
+		{"texto", []byte("não é uma imagem"), time.Time{}, false},
+		{"vazio", nil, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CaptureTime(writeFile(t, "img", tt.data))
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, esperado %v", ok, tt.ok)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("CaptureTime = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := CaptureTime(filepath.Join(t.TempDir(), "nada")); ok {
+		t.Error("arquivo ausente não deve ter data")
+	}
+}
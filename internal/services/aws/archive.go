@@ -0,0 +1,56 @@
+// s3/archive.go
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// ErrArchived indica um objeto arquivado (GLACIER/DEEP_ARCHIVE) lido sem restauração
+var ErrArchived = errors.New("o objeto está arquivado: solicite a restauração e aguarde antes de baixar")
+
+// isAWSEndpoint diz se o endpoint é da própria AWS. Provedores compatíveis
+// costumam recusar OptionalObjectAttributes nas listagens.
+func isAWSEndpoint(endpoint string) bool {
+	host := endpoint
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexAny(host, "/:"); i >= 0 {
+		host = host[:i]
+	}
+	host = strings.ToLower(host)
+	return strings.HasSuffix(host, ".amazonaws.com") || strings.HasSuffix(host, ".amazonaws.com.cn")
+}
+
+// RestoreObject pede uma cópia temporária de um objeto arquivado, mantida
+// por days dias. tier é "Standard", "Bulk" ou "Expedited".
+func (c *Client) RestoreObject(ctx context.Context, bucket, key string, days int32, tier string) error {
+	if days < 1 {
+		return fmt.Errorf("a cópia restaurada precisa durar ao menos 1 dia")
+	}
+
+	_, err := c.s3.RestoreObject(ctx, &s3.RestoreObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		RestoreRequest: &types.RestoreRequest{
+			Days: aws.Int32(days),
+			GlacierJobParameters: &types.GlacierJobParameters{
+				Tier: types.Tier(tier),
+			},
+		},
+	})
+	if isAPIError(err, "RestoreAlreadyInProgress") {
+		return fmt.Errorf("já existe uma restauração em andamento para %s", key)
+	}
+	if err != nil {
+		return fmt.Errorf("falha ao solicitar restauração: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+// s3/bucket.go
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"s3nd-files/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// isAPIError verifica se err é um erro do S3 com um dos códigos informados
+func isAPIError(err error, codes ...string) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.ErrorCode() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// GetBucketVersioning retorna "Enabled", "Suspended" ou "" (nunca ativado)
+func (c *Client) GetBucketVersioning(ctx context.Context, bucket string) (string, error) {
+	out, err := c.s3.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return "", fmt.Errorf("falha ao ler versionamento: %w", err)
+	}
+	return string(out.Status), nil
+}
+
+// SetBucketVersioning ativa ou suspende o versionamento do bucket
+func (c *Client) SetBucketVersioning(ctx context.Context, bucket string, enabled bool) error {
+	status := types.BucketVersioningStatusSuspended
+	if enabled {
+		status = types.BucketVersioningStatusEnabled
+	}
+
+	_, err := c.s3.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+		Bucket: aws.String(bucket),
+		VersioningConfiguration: &types.VersioningConfiguration{
+			Status: status,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("falha ao alterar versionamento: %w", err)
+	}
+	return nil
+}
+
+// GetLifecycleRules retorna as regras de ciclo de vida do bucket
+// (lista vazia quando o bucket não tem configuração)
+func (c *Client) GetLifecycleRules(ctx context.Context, bucket string) ([]models.LifecycleRule, error) {
+	out, err := c.s3.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+	if isAPIError(err, "NoSuchLifecycleConfiguration") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler ciclo de vida: %w", err)
+	}
+
+	rules := make([]models.LifecycleRule, 0, len(out.Rules))
+	for _, r := range out.Rules {
+		rule := models.LifecycleRule{
+			ID:          aws.ToString(r.ID),
+			Enabled:     r.Status == types.ExpirationStatusEnabled,
+			Unsupported: unsupportedLifecycle(r),
+			Original:    r,
+		}
+
+		if r.Filter != nil {
+			rule.Prefix = aws.ToString(r.Filter.Prefix)
+			if r.Filter.And != nil {
+				rule.Prefix = aws.ToString(r.Filter.And.Prefix)
+			}
+		} else if r.Prefix != nil {
+			rule.Prefix = *r.Prefix
+		}
+
+		if r.Expiration != nil {
+			rule.ExpirationDays = aws.ToInt32(r.Expiration.Days)
+		}
+		if r.NoncurrentVersionExpiration != nil {
+			rule.NoncurrentExpirationDays = aws.ToInt32(r.NoncurrentVersionExpiration.NoncurrentDays)
+		}
+		if r.AbortIncompleteMultipartUpload != nil {
+			rule.AbortMultipartDays = aws.ToInt32(r.AbortIncompleteMultipartUpload.DaysAfterInitiation)
+		}
+		for _, t := range r.Transitions {
+			rule.Transitions = append(rule.Transitions, models.LifecycleTransition{
+				Days:         aws.ToInt32(t.Days),
+				StorageClass: string(t.StorageClass),
+			})
+		}
+
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// unsupportedLifecycle descreve o que r usa além de prefixo e ações em
+// dias ("" quando o editor representa a regra inteira)
+func unsupportedLifecycle(r types.LifecycleRule) string {
+	var uses []string
+	if f := r.Filter; f != nil {
+		if f.Tag != nil || f.ObjectSizeGreaterThan != nil || f.ObjectSizeLessThan != nil || f.And != nil {
+			uses = append(uses, "filtro por tags ou tamanho")
+		}
+	}
+	if e := r.Expiration; e != nil && (e.Date != nil || aws.ToBool(e.ExpiredObjectDeleteMarker)) {
+		uses = append(uses, "expiração por data ou de marcadores de exclusão")
+	}
+	for _, t := range r.Transitions {
+		if t.Date != nil {
+			uses = append(uses, "transição por data")
+			break
+		}
+	}
+	if len(r.NoncurrentVersionTransitions) > 0 {
+		uses = append(uses, "transição de versões antigas")
+	}
+	if n := r.NoncurrentVersionExpiration; n != nil && n.NewerNoncurrentVersions != nil {
+		uses = append(uses, "número de versões antigas mantidas")
+	}
+	if len(uses) == 0 {
+		return ""
+	}
+	return "usa " + strings.Join(uses, ", ")
+}
+
+// PutLifecycleRules substitui as regras de ciclo de vida do bucket.
+// Uma lista vazia remove a configuração.
+func (c *Client) PutLifecycleRules(ctx context.Context, bucket string, rules []models.LifecycleRule) error {
+	if err := models.ValidateLifecycleRules(rules); err != nil {
+		return err
+	}
+
+	if len(rules) == 0 {
+		_, err := c.s3.DeleteBucketLifecycle(ctx, &s3.DeleteBucketLifecycleInput{
+			Bucket: aws.String(bucket),
+		})
+		if err != nil {
+			return fmt.Errorf("falha ao remover ciclo de vida: %w", err)
+		}
+		return nil
+	}
+
+	s3Rules := make([]types.LifecycleRule, 0, len(rules))
+	for _, r := range rules {
+		// Partir da regra lida do bucket, para não perder o que o modelo
+		// não representa; as não suportadas vão sem alteração
+		rule, loaded := r.Original.(types.LifecycleRule)
+		if r.Unsupported != "" {
+			if !loaded {
+				return fmt.Errorf("regra %q: regra não suportada sem a versão original", r.ID)
+			}
+			s3Rules = append(s3Rules, rule)
+			continue
+		}
+
+		rule.ID = aws.String(r.ID)
+		rule.Status = types.ExpirationStatusDisabled
+		if r.Enabled {
+			rule.Status = types.ExpirationStatusEnabled
+		}
+		rule.Prefix = nil
+		rule.Filter = &types.LifecycleRuleFilter{Prefix: aws.String(r.Prefix)}
+
+		rule.Expiration = nil
+		if r.ExpirationDays > 0 {
+			rule.Expiration = &types.LifecycleExpiration{Days: aws.Int32(r.ExpirationDays)}
+		}
+		rule.NoncurrentVersionExpiration = nil
+		if r.NoncurrentExpirationDays > 0 {
+			rule.NoncurrentVersionExpiration = &types.NoncurrentVersionExpiration{
+				NoncurrentDays: aws.Int32(r.NoncurrentExpirationDays),
+			}
+		}
+		rule.AbortIncompleteMultipartUpload = nil
+		if r.AbortMultipartDays > 0 {
+			rule.AbortIncompleteMultipartUpload = &types.AbortIncompleteMultipartUpload{
+				DaysAfterInitiation: aws.Int32(r.AbortMultipartDays),
+			}
+		}
+		rule.Transitions = nil
+		for _, t := range r.Transitions {
+			rule.Transitions = append(rule.Transitions, types.Transition{
+				Days:         aws.Int32(t.Days),
+				StorageClass: types.TransitionStorageClass(t.StorageClass),
+			})
+		}
+
+		s3Rules = append(s3Rules, rule)
+	}
+
+	_, err := c.s3.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+		LifecycleConfiguration: &types.BucketLifecycleConfiguration{
+			Rules: s3Rules,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("falha ao salvar ciclo de vida: %w", err)
+	}
+	return nil
+}
+
+// GetBucketPolicy retorna a política do bucket em JSON ("" quando não há)
+func (c *Client) GetBucketPolicy(ctx context.Context, bucket string) (string, error) {
+	out, err := c.s3.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{
+		Bucket: aws.String(bucket),
+	})
+	if isAPIError(err, "NoSuchBucketPolicy") {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("falha ao ler política: %w", err)
+	}
+	return aws.ToString(out.Policy), nil
+}
+
+// PutBucketPolicy substitui a política do bucket. Texto vazio remove a política.
+func (c *Client) PutBucketPolicy(ctx context.Context, bucket, policy string) error {
+	if strings.TrimSpace(policy) == "" {
+		_, err := c.s3.DeleteBucketPolicy(ctx, &s3.DeleteBucketPolicyInput{
+			Bucket: aws.String(bucket),
+		})
+		if err != nil {
+			return fmt.Errorf("falha ao remover política: %w", err)
+		}
+		return nil
+	}
+
+	if err := models.ValidateBucketPolicy(policy); err != nil {
+		return err
+	}
+
+	_, err := c.s3.PutBucketPolicy(ctx, &s3.PutBucketPolicyInput{
+		Bucket: aws.String(bucket),
+		Policy: aws.String(policy),
+	})
+	if err != nil {
+		return fmt.Errorf("falha ao salvar política: %w", err)
+	}
+	return nil
+}
+
+// GetCORSRules retorna as regras CORS do bucket (vazio quando não há)
+func (c *Client) GetCORSRules(ctx context.Context, bucket string) ([]models.CORSRule, error) {
+	out, err := c.s3.GetBucketCors(ctx, &s3.GetBucketCorsInput{
+		Bucket: aws.String(bucket),
+	})
+	if isAPIError(err, "NoSuchCORSConfiguration") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler CORS: %w", err)
+	}
+
+	rules := make([]models.CORSRule, 0, len(out.CORSRules))
+	for _, r := range out.CORSRules {
+		rules = append(rules, models.CORSRule{
+			ID:             aws.ToString(r.ID),
+			AllowedOrigins: r.AllowedOrigins,
+			AllowedMethods: r.AllowedMethods,
+			AllowedHeaders: r.AllowedHeaders,
+			ExposeHeaders:  r.ExposeHeaders,
+			MaxAgeSeconds:  aws.ToInt32(r.MaxAgeSeconds),
+		})
+	}
+	return rules, nil
+}
+
+// PutCORSRules substitui as regras CORS do bucket. Lista vazia remove a configuração.
+func (c *Client) PutCORSRules(ctx context.Context, bucket string, rules []models.CORSRule) error {
+	if err := models.ValidateCORSRules(rules); err != nil {
+		return err
+	}
+
+	if len(rules) == 0 {
+		_, err := c.s3.DeleteBucketCors(ctx, &s3.DeleteBucketCorsInput{
+			Bucket: aws.String(bucket),
+		})
+		if err != nil {
+			return fmt.Errorf("falha ao remover CORS: %w", err)
+		}
+		return nil
+	}
+
+	s3Rules := make([]types.CORSRule, 0, len(rules))
+	for _, r := range rules {
+		rule := types.CORSRule{
+			AllowedOrigins: r.AllowedOrigins,
+			AllowedMethods: r.AllowedMethods,
+			AllowedHeaders: r.AllowedHeaders,
+			ExposeHeaders:  r.ExposeHeaders,
+		}
+		if r.ID != "" {
+			rule.ID = aws.String(r.ID)
+		}
+		if r.MaxAgeSeconds > 0 {
+			rule.MaxAgeSeconds = aws.Int32(r.MaxAgeSeconds)
+		}
+		s3Rules = append(s3Rules, rule)
+	}
+
+	_, err := c.s3.PutBucketCors(ctx, &s3.PutBucketCorsInput{
+		Bucket:            aws.String(bucket),
+		CORSConfiguration: &types.CORSConfiguration{CORSRules: s3Rules},
+	})
+	if err != nil {
+		return fmt.Errorf("falha ao salvar CORS: %w", err)
+	}
+	return nil
+}
@@ -5,21 +5,37 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"s3nd-files/internal/envelope"
 	"s3nd-files/internal/models"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 type Client struct {
 	s3 *s3.Client
+	// Criptografia padrão; a chave SSE-C pode ser trocada durante o uso
+	sseMu sync.RWMutex
+	sse   models.Encryption
+	// Chave mestra da criptografia no cliente, também trocada durante o uso
+	cseMu sync.RWMutex
+	cse   *envelope.MasterKey
+	// Registro dos envios multipart, para retomá-los (opcional)
+	journal UploadJournal
+	// O endpoint aceita OptionalObjectAttributes (só a AWS)
+	restoreAttrs bool
 }
 
 type Config struct {
@@ -32,6 +48,12 @@ type Config struct {
 	DisableSSL       bool   // Para forçar HTTP (apenas dev/test)
 	ForcePathStyle   bool   // Para MinIO e alguns endpoints S3
 	CustomCACertPath string // Para certificados auto-assinados
+	// Criptografia padrão dos uploads; a chave SSE-C também é usada em leituras
+	Encryption models.Encryption
+	// Chave mestra da criptografia no cliente (opcional)
+	MasterKey *envelope.MasterKey
+	// Registro dos envios em partes deste app (opcional)
+	Uploads UploadJournal
 }
 
 func New(cfg Config) (*Client, error) {
@@ -42,6 +64,9 @@ func New(cfg Config) (*Client, error) {
 	if cfg.AccessKey == "" || cfg.SecretKey == "" {
 		return nil, fmt.Errorf("credenciais não podem ser vazias")
 	}
+	if err := cfg.Encryption.Validate(); err != nil {
+		return nil, err
+	}
 	
 	// Configurar resolvedor de endpoint customizado
 	customResolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
@@ -124,7 +149,13 @@ func New(cfg Config) (*Client, error) {
 
 	s3Client := s3.NewFromConfig(awsCfg, s3Opts...)
 
-	return &Client{s3: s3Client}, nil
+	return &Client{
+		s3:           s3Client,
+		sse:          cfg.Encryption,
+		cse:          cfg.MasterKey,
+		journal:      cfg.Uploads,
+		restoreAttrs: isAWSEndpoint(cfg.Endpoint),
+	}, nil
 }
 
 func (c *Client) ListBuckets(ctx context.Context) ([]string, error) {
@@ -227,29 +258,17 @@ func (c *Client) ListObjects(ctx context.Context, bucket, prefix string) ([]mode
 	}
 
 	// Ordenar
-	sortItems(allItems)
+	models.SortItems(allItems)
 	
 	return allItems, nil
 }
 
-// Adicione esta função auxiliar para ordenar
-func sortItems(items []models.Item) {
-	sort.Slice(items, func(i, j int) bool {
-		// Pastas primeiro
-		if items[i].Type == models.Folder && items[j].Type != models.Folder {
-			return true
-		}
-		if items[i].Type != models.Folder && items[j].Type == models.Folder {
-			return false
-		}
-		// Depois ordenar por nome
-		return strings.ToLower(items[i].Name) < strings.ToLower(items[j].Name)
-	})
-}
 // Adicione após a função ListObjects existente
 
-// ListObjectsPaginated lista objetos com paginação
-func (c *Client) ListObjectsPaginated(ctx context.Context, bucket, prefix string, maxKeys int32) ([]models.Item, string, error) {
+// ListObjectsPaginated lista uma página de objetos. Passe o token retornado
+// pela chamada anterior para continuar; token vazio começa do início.
+// O token retornado é vazio quando não há mais páginas.
+func (c *Client) ListObjectsPaginated(ctx context.Context, bucket, prefix, continuationToken string, maxKeys int32) ([]models.Item, string, error) {
 	if bucket == "" {
 		return nil, "", fmt.Errorf("nome do bucket não pode ser vazio")
 	}
@@ -260,6 +279,13 @@ func (c *Client) ListObjectsPaginated(ctx context.Context, bucket, prefix string
 		Delimiter: aws.String("/"),
 		MaxKeys:   aws.Int32(maxKeys), // Limitar número de resultados
 	}
+	if c.restoreAttrs {
+		// Trazer o estado de restauração de objetos arquivados
+		input.OptionalObjectAttributes = []types.OptionalObjectAttributes{types.OptionalObjectAttributesRestoreStatus}
+	}
+	if continuationToken != "" {
+		input.ContinuationToken = aws.String(continuationToken)
+	}
 
 	result, err := c.s3.ListObjectsV2(ctx, input)
 	if err != nil {
@@ -296,68 +322,423 @@ func (c *Client) ListObjectsPaginated(ctx context.Context, bucket, prefix string
 				continue
 			}
 			
-			items = append(items, models.Item{
-				Name:   name,
-				Type:   models.File,
-				Prefix: key,
-			})
+			item := models.Item{
+				Name:         name,
+				Type:         models.File,
+				Prefix:       key,
+				StorageClass: string(obj.StorageClass),
+			}
+			if rs := obj.RestoreStatus; rs != nil {
+				item.Restore.State = models.RestoreDone
+				if aws.ToBool(rs.IsRestoreInProgress) {
+					item.Restore.State = models.RestoreInProgress
+				}
+				item.Restore.Expiry = aws.ToTime(rs.RestoreExpiryDate)
+			}
+			items = append(items, item)
 		}
 	}
 
 	// Retornar next token se houver mais resultados
 	nextToken := ""
-	if result.NextContinuationToken != nil {
+	if result.NextContinuationToken != nil && aws.ToBool(result.IsTruncated) {
 		nextToken = *result.NextContinuationToken
 	}
 
 	return items, nextToken, nil
 }
 
-// CountObjects conta quantos objetos tem no prefixo
-func (c *Client) CountObjects(ctx context.Context, bucket, prefix string) (int, error) {
+// WalkObjects percorre recursivamente todos os objetos sob o prefixo,
+// página por página, chamando fn para cada um. Se fn retornar erro a
+// listagem é interrompida e o erro é devolvido.
+func (c *Client) WalkObjects(ctx context.Context, bucket, prefix string, fn func(models.Object) error) error {
 	if bucket == "" {
-		return 0, fmt.Errorf("nome do bucket não pode ser vazio")
+		return fmt.Errorf("nome do bucket não pode ser vazio")
 	}
 
-	// Usar MaxKeys=0 para contar rapidamente (algumas implementações S3 suportam)
-	input := &s3.ListObjectsV2Input{
-		Bucket:    aws.String(bucket),
-		Prefix:    aws.String(prefix),
-		Delimiter: aws.String("/"),
-		MaxKeys:   aws.Int32(10000), // Limitar para resposta rápida
-	}
+	paginator := s3.NewListObjectsV2Paginator(c.s3, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
 
-	result, err := c.s3.ListObjectsV2(ctx, input)
-	if err != nil {
-		return 0, fmt.Errorf("falha ao contar objetos: %w", err)
-	}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("falha ao listar objetos: %w", err)
+		}
 
-	// KeyCount é o número de chaves retornadas (não o total)
-	// Para uma contagem exata precisaríamos paginar tudo
-	// Esta é uma estimativa rápida
-	total := int(*result.KeyCount)
-	
-	// Se houver mais resultados, estimar um total maior
-	if result.NextContinuationToken != nil {
-		total = 1000 // Estimativa conservadora
+		for _, obj := range page.Contents {
+			if obj.Key == nil {
+				continue
+			}
+
+			storageClass := string(obj.StorageClass)
+			if storageClass == "" {
+				storageClass = "STANDARD"
+			}
+
+			err := fn(models.Object{
+				Key:          *obj.Key,
+				Size:         aws.ToInt64(obj.Size),
+				LastModified: aws.ToTime(obj.LastModified),
+				StorageClass: storageClass,
+				ETag:         strings.Trim(aws.ToString(obj.ETag), `"`),
+			})
+			if err != nil {
+				return err
+			}
+		}
 	}
 
-	return total, nil
+	return nil
 }
 
 // UploadFile faz upload de um arquivo para o S3
-func (c *Client) UploadFile(ctx context.Context, bucket, key, filepath string) error {
+func (c *Client) UploadFile(ctx context.Context, bucket, key, filepath string, opts models.UploadOptions) error {
+	enc := c.Encryption()
+	if opts.Encryption != nil {
+		enc = *opts.Encryption
+	}
+	if err := enc.Validate(); err != nil {
+		return err
+	}
+
 	file, err := os.Open(filepath)
 	if err != nil {
 		return fmt.Errorf("falha ao abrir arquivo: %w", err)
 	}
 	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("falha ao abrir arquivo: %w", err)
+	}
+	body := file
+	local := models.PendingUpload{Path: filepath, Size: stat.Size(), ModTime: stat.ModTime()}
 
-	_, err = c.s3.PutObject(ctx, &s3.PutObjectInput{
+	input := &s3.PutObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
 		Body:   file,
+	}
+	applyPutEncryption(input, enc)
+	applyPutHeaders(input, opts.Headers)
+	if opts.StorageClass != "" {
+		input.StorageClass = types.StorageClass(opts.StorageClass)
+	}
+	if opts.Retention.Mode != "" {
+		if err := opts.Retention.Validate(time.Now()); err != nil {
+			return err
+		}
+		input.ObjectLockMode = types.ObjectLockMode(opts.Retention.Mode)
+		input.ObjectLockRetainUntilDate = aws.Time(opts.Retention.RetainUntil.UTC())
+	}
+	if opts.LegalHold {
+		input.ObjectLockLegalHoldStatus = types.ObjectLockLegalHoldStatusOn
+	}
+	if opts.IfAbsent {
+		input.IfNoneMatch = aws.String("*")
+	}
+	if len(opts.Tags) > 0 {
+		if err := models.ValidateTags(opts.Tags); err != nil {
+			return err
+		}
+		input.Tagging = taggingHeader(opts.Tags)
+	}
+
+	if opts.ClientSide {
+		sealed, err := c.encryptFile(file, input)
+		if err != nil {
+			return err
+		}
+		// O conteúdo gravado é o envelope cifrado, não o original comprimido
+		input.ContentEncoding = nil
+		defer func() {
+			sealed.Close()
+			os.Remove(sealed.Name())
+		}()
+		body = sealed
+
+		// O conteúdo cifrado muda a cada envio: não há como retomar
+		sealedStat, err := sealed.Stat()
+		if err != nil {
+			return err
+		}
+		local = models.PendingUpload{Size: sealedStat.Size(), ModTime: stat.ModTime()}
+	}
+
+	if local.Size >= multipartThreshold {
+		return c.putMultipart(ctx, input, body, local)
+	}
+	input.Body = body
+	_, err = c.s3.PutObject(ctx, input)
+	if notImplemented(err) && input.IfNoneMatch != nil {
+		// Provedor sem gravação condicional: a interface já conferiu o destino
+		if _, err = body.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("falha ao enviar arquivo: %w", err)
+		}
+		input.IfNoneMatch = nil
+		_, err = c.s3.PutObject(ctx, input)
+	}
+	if existsError(err) {
+		return fmt.Errorf("falha ao enviar arquivo: %w", models.ErrObjectExists)
+	}
+	if err != nil {
+		return fmt.Errorf("falha ao enviar arquivo: %w", err)
+	}
+	return nil
+}
+
+// DownloadFile baixa bucket/key para o caminho local dest
+func (c *Client) DownloadFile(ctx context.Context, bucket, key, dest string) error {
+	return c.download(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}, dest)
+}
+
+// DownloadFileVersion baixa uma versão específica de bucket/key
+func (c *Client) DownloadFileVersion(ctx context.Context, bucket, key, versionID, dest string) error {
+	return c.download(ctx, &s3.GetObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: aws.String(versionID),
+	}, dest)
+}
+
+func (c *Client) download(ctx context.Context, input *s3.GetObjectInput, dest string) error {
+	out, err := c.getObject(ctx, input)
+	if err != nil {
+		return err
+	}
+	defer out.Body.Close()
+
+	body, err := c.decryptBody(out.Body, out.Metadata)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("falha ao criar arquivo: %w", err)
+	}
+
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		// Não deixar para trás um arquivo parcial ou com conteúdo não verificado
+		os.Remove(dest)
+		return fmt.Errorf("falha ao gravar arquivo: %w", err)
+	}
+
+	return file.Close()
+}
+
+// DeleteObject remove bucket/key
+func (c *Client) DeleteObject(ctx context.Context, bucket, key string) error {
+	_, err := c.s3.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
 	})
-	
-	return err
+	if err != nil {
+		return fmt.Errorf("falha ao excluir objeto: %w", err)
+	}
+	return nil
+}
+
+// PresignGetObject gera uma URL temporária para baixar bucket/key sem credenciais
+func (c *Client) PresignGetObject(ctx context.Context, bucket, key string, expires time.Duration) (string, error) {
+	presigner := s3.NewPresignClient(c.s3)
+	req, err := presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", fmt.Errorf("falha ao gerar link: %w", err)
+	}
+	return req.URL, nil
+}
+
+// GetObjectTags retorna as tags de bucket/key
+func (c *Client) GetObjectTags(ctx context.Context, bucket, key string) (map[string]string, error) {
+	return c.versionTags(ctx, bucket, key, "")
+}
+
+// versionTags retorna as tags de uma versão (a atual com versionID vazio)
+func (c *Client) versionTags(ctx context.Context, bucket, key, versionID string) (map[string]string, error) {
+	input := &s3.GetObjectTaggingInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if versionID != "" {
+		input.VersionId = aws.String(versionID)
+	}
+	out, err := c.s3.GetObjectTagging(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler tags: %w", err)
+	}
+
+	tags := make(map[string]string, len(out.TagSet))
+	for _, t := range out.TagSet {
+		tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	return tags, nil
+}
+
+// taggingHeader codifica tags no formato do cabeçalho x-amz-tagging
+func taggingHeader(tags map[string]string) *string {
+	tagging := url.Values{}
+	for k, v := range tags {
+		tagging.Set(k, v)
+	}
+	return aws.String(tagging.Encode())
+}
+
+// PutObjectTags substitui as tags de bucket/key. Sem tags, remove todas.
+func (c *Client) PutObjectTags(ctx context.Context, bucket, key string, tags map[string]string) error {
+	if err := models.ValidateTags(tags); err != nil {
+		return err
+	}
+
+	if len(tags) == 0 {
+		_, err := c.s3.DeleteObjectTagging(ctx, &s3.DeleteObjectTaggingInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return fmt.Errorf("falha ao remover tags: %w", err)
+		}
+		return nil
+	}
+
+	tagSet := make([]types.Tag, 0, len(tags))
+	for k, v := range tags {
+		tagSet = append(tagSet, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	_, err := c.s3.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
+		Bucket:  aws.String(bucket),
+		Key:     aws.String(key),
+		Tagging: &types.Tagging{TagSet: tagSet},
+	})
+	if err != nil {
+		return fmt.Errorf("falha ao salvar tags: %w", err)
+	}
+	return nil
+}
+
+// WalkObjectVersions percorre todas as versões e marcadores de exclusão
+// sob o prefixo, chamando fn para cada um
+func (c *Client) WalkObjectVersions(ctx context.Context, bucket, prefix string, fn func(models.ObjectVersion) error) error {
+	if bucket == "" {
+		return fmt.Errorf("nome do bucket não pode ser vazio")
+	}
+
+	paginator := s3.NewListObjectVersionsPaginator(c.s3, &s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("falha ao listar versões: %w", err)
+		}
+
+		for _, v := range page.Versions {
+			err := fn(models.ObjectVersion{
+				Key:          aws.ToString(v.Key),
+				VersionID:    aws.ToString(v.VersionId),
+				IsLatest:     aws.ToBool(v.IsLatest),
+				Size:         aws.ToInt64(v.Size),
+				LastModified: aws.ToTime(v.LastModified),
+				StorageClass: string(v.StorageClass),
+				ETag:         strings.Trim(aws.ToString(v.ETag), `"`),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, m := range page.DeleteMarkers {
+			err := fn(models.ObjectVersion{
+				Key:            aws.ToString(m.Key),
+				VersionID:      aws.ToString(m.VersionId),
+				IsLatest:       aws.ToBool(m.IsLatest),
+				IsDeleteMarker: true,
+				LastModified:   aws.ToTime(m.LastModified),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ListObjectVersions retorna o histórico de versões de uma chave, da mais
+// recente para a mais antiga
+func (c *Client) ListObjectVersions(ctx context.Context, bucket, key string) ([]models.ObjectVersion, error) {
+	var versions []models.ObjectVersion
+	err := c.WalkObjectVersions(ctx, bucket, key, func(v models.ObjectVersion) error {
+		// O prefixo também traz chaves que começam com key (ex: key.bak)
+		if v.Key == key {
+			versions = append(versions, v)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].LastModified.After(versions[j].LastModified)
+	})
+	return versions, nil
+}
+
+// RestoreVersion torna uma versão antiga a atual, copiando-a sobre a
+// própria chave. A versão original continua no histórico.
+func (c *Client) RestoreVersion(ctx context.Context, bucket, key, versionID string) error {
+	if err := c.copyInPlace(ctx, bucket, key, versionID); err != nil {
+		return fmt.Errorf("falha ao restaurar versão: %w", err)
+	}
+	return nil
+}
+
+// copySource monta o valor de CopySource para uma chave (e versão opcional)
+func copySource(bucket, key, versionID string) string {
+	source := bucket + "/" + url.PathEscape(key)
+	if versionID != "" {
+		source += "?versionId=" + url.QueryEscape(versionID)
+	}
+	return source
+}
+
+// DeleteVersion exclui permanentemente uma versão ou marcador de exclusão
+func (c *Client) DeleteVersion(ctx context.Context, bucket, key, versionID string) error {
+	_, err := c.s3.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: aws.String(versionID),
+	})
+	if err != nil {
+		return fmt.Errorf("falha ao excluir versão: %w", err)
+	}
+	return nil
+}
+
+// Recover faz candidate.Target voltar a ser a versão atual. Se só houver
+// marcadores de exclusão depois dela, os marcadores são removidos; caso
+// contrário a versão é copiada sobre a chave.
+func (c *Client) Recover(ctx context.Context, bucket string, candidate models.RecoveryCandidate) error {
+	if !candidate.OnlyDeleteMarkers() {
+		return c.RestoreVersion(ctx, bucket, candidate.Key, candidate.Target.VersionID)
+	}
+
+	for _, marker := range candidate.Newer {
+		if err := c.DeleteVersion(ctx, bucket, candidate.Key, marker.VersionID); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,148 @@
+// aws/copy.go
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"s3nd-files/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Maior objeto aceito pelo CopyObject; acima disso a cópia é feita em
+// partes com UploadPartCopy
+const maxCopySize = 5 << 30
+
+// CopyObject copia srcBucket/srcKey para dstBucket/dstKey no servidor,
+// mantendo metadados, tags, classe de armazenamento e criptografia
+func (c *Client) CopyObject(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	info, err := c.headObject(ctx, srcBucket, srcKey, "")
+	if err != nil {
+		return fmt.Errorf("falha ao copiar objeto: %w", err)
+	}
+	if info.Size > maxCopySize {
+		return c.copyMultipart(ctx, srcBucket, srcKey, dstBucket, dstKey, info)
+	}
+
+	input := &s3.CopyObjectInput{
+		Bucket:     aws.String(dstBucket),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(copySource(srcBucket, srcKey, "")),
+	}
+	// Sem classe explícita a cópia vira STANDARD
+	if info.StorageClass != "" && info.StorageClass != "STANDARD" {
+		input.StorageClass = types.StorageClass(info.StorageClass)
+	}
+	c.copyEncryption(input, info)
+
+	if _, err := c.s3.CopyObject(ctx, input); err != nil {
+		return c.sseError(err, "copiar objeto")
+	}
+	return nil
+}
+
+// copyMultipart copia um objeto grande em partes. O envio em partes não
+// herda nada da origem, então cabeçalhos, metadados, tags, classe e
+// criptografia são repetidos a partir de info.
+func (c *Client) copyMultipart(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string, info models.ObjectInfo) error {
+	// As tags da mesma versão; sem suporte a tags no provedor, nenhuma
+	tags, err := c.versionTags(ctx, srcBucket, srcKey, info.VersionID)
+	if err != nil && !notImplemented(err) {
+		return fmt.Errorf("falha ao copiar objeto: %w", err)
+	}
+
+	create := &s3.CreateMultipartUploadInput{
+		Bucket:   aws.String(dstBucket),
+		Key:      aws.String(dstKey),
+		Metadata: info.Metadata,
+	}
+	for _, h := range []struct {
+		dst **string
+		v   string
+	}{
+		{&create.ContentType, info.ContentType},
+		{&create.ContentEncoding, info.ContentEncoding},
+		{&create.CacheControl, info.CacheControl},
+		{&create.ContentDisposition, info.ContentDisposition},
+	} {
+		if h.v != "" {
+			*h.dst = aws.String(h.v)
+		}
+	}
+	if info.StorageClass != "" && info.StorageClass != "STANDARD" {
+		create.StorageClass = types.StorageClass(info.StorageClass)
+	}
+	if len(tags) > 0 {
+		create.Tagging = taggingHeader(tags)
+	}
+
+	// Mesmo esquema de copyEncryption, para o envio em partes
+	var algorithm, customer, customerMD5 *string
+	switch info.Encryption {
+	case models.SSES3:
+		create.ServerSideEncryption = types.ServerSideEncryptionAes256
+	case models.SSEKMS:
+		create.ServerSideEncryption = types.ServerSideEncryptionAwsKms
+		if info.KMSKeyID != "" {
+			create.SSEKMSKeyId = aws.String(info.KMSKeyID)
+		}
+	case models.SSEC:
+		algorithm, customer, customerMD5 = customerKey(c.customerKeyBytes())
+		create.SSECustomerAlgorithm, create.SSECustomerKey, create.SSECustomerKeyMD5 = algorithm, customer, customerMD5
+	}
+
+	out, err := c.s3.CreateMultipartUpload(ctx, create)
+	if err != nil {
+		return c.sseError(err, "copiar objeto")
+	}
+	uploadID := aws.ToString(out.UploadId)
+
+	// A versão lida no HEAD, para que todas as partes venham do mesmo conteúdo
+	source := aws.String(copySource(srcBucket, srcKey, info.VersionID))
+	step := partSize(info.Size)
+	var completed []types.CompletedPart
+	for n, offset := int32(1), int64(0); offset < info.Size; n, offset = n+1, offset+step {
+		end := min(offset+step, info.Size) - 1
+		part, err := c.s3.UploadPartCopy(ctx, &s3.UploadPartCopyInput{
+			Bucket:          aws.String(dstBucket),
+			Key:             aws.String(dstKey),
+			UploadId:        aws.String(uploadID),
+			PartNumber:      aws.Int32(n),
+			CopySource:      source,
+			CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", offset, end)),
+
+			CopySourceSSECustomerAlgorithm: algorithm,
+			CopySourceSSECustomerKey:       customer,
+			CopySourceSSECustomerKeyMD5:    customerMD5,
+			SSECustomerAlgorithm:           algorithm,
+			SSECustomerKey:                 customer,
+			SSECustomerKeyMD5:              customerMD5,
+		})
+		if err != nil {
+			c.AbortMultipartUpload(context.Background(), dstBucket, dstKey, uploadID)
+			return c.sseError(err, fmt.Sprintf("copiar parte %d", n))
+		}
+		completed = append(completed, types.CompletedPart{
+			PartNumber: aws.Int32(n),
+			ETag:       part.CopyPartResult.ETag,
+		})
+	}
+
+	_, err = c.s3.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:               aws.String(dstBucket),
+		Key:                  aws.String(dstKey),
+		UploadId:             aws.String(uploadID),
+		MultipartUpload:      &types.CompletedMultipartUpload{Parts: completed},
+		SSECustomerAlgorithm: algorithm,
+		SSECustomerKey:       customer,
+		SSECustomerKeyMD5:    customerMD5,
+	})
+	if err != nil {
+		c.AbortMultipartUpload(context.Background(), dstBucket, dstKey, uploadID)
+		return fmt.Errorf("falha ao concluir cópia em partes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// s3/cse.go
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"s3nd-files/internal/envelope"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ErrClientKeyRequired indica um objeto cifrado no cliente lido sem a chave mestra
+var ErrClientKeyRequired = errors.New("o objeto foi cifrado no cliente: informe a senha ou o arquivo de chave para abri-lo")
+
+// SetMasterKey define a chave mestra da criptografia no cliente
+func (c *Client) SetMasterKey(m *envelope.MasterKey) {
+	c.cseMu.Lock()
+	defer c.cseMu.Unlock()
+	c.cse = m
+}
+
+// masterKey retorna a chave mestra carregada (nil se não houver). Cada
+// operação lê uma vez só, para não misturar chaves se ela for trocada.
+func (c *Client) masterKey() *envelope.MasterKey {
+	c.cseMu.RLock()
+	defer c.cseMu.RUnlock()
+	return c.cse
+}
+
+// HasMasterKey informa se há chave mestra carregada
+func (c *Client) HasMasterKey() bool {
+	return c.masterKey() != nil
+}
+
+// encryptFile cifra src em um arquivo temporário e marca input com os
+// metadados do envelope. Quem chama fecha e remove o arquivo.
+func (c *Client) encryptFile(src io.Reader, input *s3.PutObjectInput) (*os.File, error) {
+	master := c.masterKey()
+	if master == nil {
+		return nil, fmt.Errorf("criptografia no cliente exige uma senha ou arquivo de chave")
+	}
+
+	dataKey, meta, err := master.NewDataKey()
+	if err != nil {
+		return nil, fmt.Errorf("falha ao gerar chave de dados: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", "s3nd-cse-*")
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar arquivo temporário: %w", err)
+	}
+	fail := func(err error) (*os.File, error) {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+
+	size, err := envelope.Encrypt(tmp, src, dataKey)
+	if err != nil {
+		return fail(fmt.Errorf("falha ao cifrar arquivo: %w", err))
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fail(err)
+	}
+
+	meta[envelope.MetaSize] = strconv.FormatInt(size, 10)
+	if input.Metadata == nil {
+		input.Metadata = make(map[string]string)
+	}
+	for k, v := range meta {
+		input.Metadata[k] = v
+	}
+	input.ContentType = aws.String(envelope.ContentType)
+	return tmp, nil
+}
+
+// decryptBody devolve body decifrado quando os metadados indicam
+// criptografia no cliente, ou o próprio body caso contrário
+func (c *Client) decryptBody(body io.Reader, meta map[string]string) (io.Reader, error) {
+	if !envelope.IsEncrypted(meta) {
+		return body, nil
+	}
+	master := c.masterKey()
+	if master == nil {
+		return nil, ErrClientKeyRequired
+	}
+
+	dataKey, err := master.DataKey(meta)
+	if err != nil {
+		return nil, err
+	}
+	return envelope.NewReader(body, dataKey)
+}
@@ -0,0 +1,63 @@
+// aws/exists.go
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"s3nd-files/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// StatObjects verifica com HEAD quais das chaves existem em bucket. Chaves
+// ausentes ficam fora do resultado. Objetos SSE-C sem a chave carregada
+// aparecem só com a chave.
+func (c *Client) StatObjects(ctx context.Context, bucket string, keys []string) (map[string]models.Object, error) {
+	found := make(map[string]models.Object)
+	for _, key := range keys {
+		out, err := c.s3.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		switch {
+		case isNotFound(err):
+			continue
+		case needsCustomerKey(err):
+			found[key] = models.Object{Key: key}
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("falha ao verificar %s: %w", key, err)
+		}
+
+		found[key] = models.Object{
+			Key:          key,
+			Size:         aws.ToInt64(out.ContentLength),
+			LastModified: aws.ToTime(out.LastModified),
+			StorageClass: string(out.StorageClass),
+			ETag:         strings.Trim(aws.ToString(out.ETag), `"`),
+		}
+	}
+	return found, nil
+}
+
+// isNotFound indica um HEAD de objeto inexistente
+func isNotFound(err error) bool {
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return true
+	}
+	var respErr *smithyhttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound
+}
+
+// existsError traduz a recusa de uma gravação condicional (If-None-Match)
+func existsError(err error) bool {
+	return isAPIError(err, "PreconditionFailed", "ConditionalRequestConflict")
+}
@@ -0,0 +1,33 @@
+// aws/headers.go
+package aws
+
+import (
+	"maps"
+
+	"s3nd-files/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// applyPutHeaders grava em input os cabeçalhos preenchidos de h
+func applyPutHeaders(input *s3.PutObjectInput, h models.ObjectHeaders) {
+	if h.ContentType != "" {
+		input.ContentType = aws.String(h.ContentType)
+	}
+	if h.CacheControl != "" {
+		input.CacheControl = aws.String(h.CacheControl)
+	}
+	if h.ContentEncoding != "" {
+		input.ContentEncoding = aws.String(h.ContentEncoding)
+	}
+	if h.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(h.ContentDisposition)
+	}
+	if len(h.Metadata) > 0 {
+		if input.Metadata == nil {
+			input.Metadata = make(map[string]string, len(h.Metadata))
+		}
+		maps.Copy(input.Metadata, h.Metadata)
+	}
+}
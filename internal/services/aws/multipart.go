@@ -0,0 +1,308 @@
+// s3/multipart.go
+package aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"s3nd-files/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Arquivos a partir deste tamanho são enviados em partes, com retomada
+const (
+	multipartThreshold = 64 << 20
+	minPartSize        = 16 << 20
+	maxParts           = 10000
+)
+
+// UploadJournal guarda os envios multipart iniciados pelo app, para
+// retomá-los e para distingui-los de envios de outras ferramentas
+type UploadJournal interface {
+	PendingUploads() []models.PendingUpload
+	SavePendingUpload(p models.PendingUpload) error
+	ForgetPendingUpload(uploadID string) error
+}
+
+// partSize escolhe o tamanho das partes de forma determinística, para que
+// uma retomada divida o arquivo do mesmo jeito
+func partSize(size int64) int64 {
+	part := int64(minPartSize)
+	if need := (size + maxParts - 1) / maxParts; need > part {
+		part = (need + 1<<20 - 1) &^ (1<<20 - 1)
+	}
+	return part
+}
+
+// findPending procura no registro um envio do mesmo arquivo local para bucket/key
+func (c *Client) findPending(bucket, key, path string, size int64, modTime time.Time) (models.PendingUpload, bool) {
+	if c.journal == nil {
+		return models.PendingUpload{}, false
+	}
+	for _, p := range c.journal.PendingUploads() {
+		if p.Bucket == bucket && p.Key == key && p.Matches(path, size, modTime) {
+			return p, true
+		}
+	}
+	return models.PendingUpload{}, false
+}
+
+// putMultipart envia body em partes. O envio é registrado antes da primeira
+// parte; se falhar, fica pendente e é retomado no próximo envio do mesmo
+// arquivo com as mesmas opções (local.Path vazio desativa a retomada).
+func (c *Client) putMultipart(ctx context.Context, input *s3.PutObjectInput, body io.ReaderAt, local models.PendingUpload) error {
+	bucket, key := aws.ToString(input.Bucket), aws.ToString(input.Key)
+	local.Bucket, local.Key = bucket, key
+
+	local.Options = uploadOptions(input)
+
+	done := make(map[int32]types.Part)
+	if p, ok := c.findPending(bucket, key, local.Path, local.Size, local.ModTime); ok {
+		if p.Options != local.Options {
+			// As partes foram enviadas com outras opções: descartar
+			if err := c.AbortMultipartUpload(ctx, bucket, key, p.UploadID); err != nil {
+				return err
+			}
+		} else if parts, err := c.listParts(ctx, bucket, key, p.UploadID); err == nil {
+			local.UploadID = p.UploadID
+			for _, part := range parts {
+				done[aws.ToInt32(part.PartNumber)] = part
+			}
+		} else if isAPIError(err, "NoSuchUpload") {
+			// O envio foi abortado ou expirou: começar de novo
+			c.journal.ForgetPendingUpload(p.UploadID)
+		} else {
+			// O registro fica para uma próxima tentativa
+			return fmt.Errorf("falha ao retomar envio em partes: %w", err)
+		}
+	}
+
+	if local.UploadID == "" {
+		out, err := c.s3.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+			Bucket:                    input.Bucket,
+			Key:                       input.Key,
+			ChecksumAlgorithm:         types.ChecksumAlgorithmCrc32,
+			ContentType:               input.ContentType,
+			CacheControl:              input.CacheControl,
+			ContentEncoding:           input.ContentEncoding,
+			ContentDisposition:        input.ContentDisposition,
+			Metadata:                  input.Metadata,
+			ServerSideEncryption:      input.ServerSideEncryption,
+			SSEKMSKeyId:               input.SSEKMSKeyId,
+			SSECustomerAlgorithm:      input.SSECustomerAlgorithm,
+			SSECustomerKey:            input.SSECustomerKey,
+			SSECustomerKeyMD5:         input.SSECustomerKeyMD5,
+			StorageClass:              input.StorageClass,
+			Tagging:                   input.Tagging,
+			ObjectLockMode:            input.ObjectLockMode,
+			ObjectLockRetainUntilDate: input.ObjectLockRetainUntilDate,
+			ObjectLockLegalHoldStatus: input.ObjectLockLegalHoldStatus,
+		})
+		if err != nil {
+			return fmt.Errorf("falha ao iniciar envio em partes: %w", err)
+		}
+		local.UploadID = aws.ToString(out.UploadId)
+		if c.journal != nil {
+			if err := c.journal.SavePendingUpload(local); err != nil {
+				return err
+			}
+		}
+	}
+
+	step := partSize(local.Size)
+	var completed []types.CompletedPart
+	for n, offset := int32(1), int64(0); offset < local.Size; n, offset = n+1, offset+step {
+		length := min(step, local.Size-offset)
+
+		if part, ok := done[n]; ok && aws.ToInt64(part.Size) == length {
+			completed = append(completed, types.CompletedPart{
+				PartNumber:    part.PartNumber,
+				ETag:          part.ETag,
+				ChecksumCRC32: part.ChecksumCRC32,
+			})
+			continue
+		}
+
+		out, err := c.s3.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:               input.Bucket,
+			Key:                  input.Key,
+			UploadId:             aws.String(local.UploadID),
+			PartNumber:           aws.Int32(n),
+			Body:                 io.NewSectionReader(body, offset, length),
+			ContentLength:        aws.Int64(length),
+			ChecksumAlgorithm:    types.ChecksumAlgorithmCrc32,
+			SSECustomerAlgorithm: input.SSECustomerAlgorithm,
+			SSECustomerKey:       input.SSECustomerKey,
+			SSECustomerKeyMD5:    input.SSECustomerKeyMD5,
+		})
+		if err != nil {
+			return fmt.Errorf("falha ao enviar parte %d (o envio pode ser retomado): %w", n, err)
+		}
+		completed = append(completed, types.CompletedPart{
+			PartNumber:    aws.Int32(n),
+			ETag:          out.ETag,
+			ChecksumCRC32: out.ChecksumCRC32,
+		})
+	}
+
+	complete := &s3.CompleteMultipartUploadInput{
+		Bucket:               input.Bucket,
+		Key:                  input.Key,
+		UploadId:             aws.String(local.UploadID),
+		MultipartUpload:      &types.CompletedMultipartUpload{Parts: completed},
+		SSECustomerAlgorithm: input.SSECustomerAlgorithm,
+		SSECustomerKey:       input.SSECustomerKey,
+		SSECustomerKeyMD5:    input.SSECustomerKeyMD5,
+		IfNoneMatch:          input.IfNoneMatch,
+	}
+	_, err := c.s3.CompleteMultipartUpload(ctx, complete)
+	if notImplemented(err) && complete.IfNoneMatch != nil {
+		complete.IfNoneMatch = nil
+		_, err = c.s3.CompleteMultipartUpload(ctx, complete)
+	}
+	if existsError(err) {
+		// A chave foi ocupada durante o envio: descartar as partes
+		c.AbortMultipartUpload(ctx, bucket, key, local.UploadID)
+		return fmt.Errorf("falha ao concluir envio em partes: %w", models.ErrObjectExists)
+	}
+	if err != nil {
+		return fmt.Errorf("falha ao concluir envio em partes: %w", err)
+	}
+	if c.journal != nil {
+		c.journal.ForgetPendingUpload(local.UploadID)
+	}
+	return nil
+}
+
+// uploadOptions resume as opções com que o envio em partes é criado. A
+// chave SSE-C entra só pelo MD5, já que o resumo fica gravado em disco.
+func uploadOptions(input *s3.PutObjectInput) string {
+	h := sha256.New()
+	field := func(v string) { fmt.Fprintf(h, "%q\n", v) }
+
+	field(aws.ToString(input.ContentType))
+	field(aws.ToString(input.CacheControl))
+	field(aws.ToString(input.ContentEncoding))
+	field(aws.ToString(input.ContentDisposition))
+	for _, k := range slices.Sorted(maps.Keys(input.Metadata)) {
+		field(k + "=" + input.Metadata[k])
+	}
+	field(string(input.ServerSideEncryption))
+	field(aws.ToString(input.SSEKMSKeyId))
+	field(aws.ToString(input.SSECustomerKeyMD5))
+	field(string(input.StorageClass))
+	field(aws.ToString(input.Tagging))
+	field(string(input.ObjectLockMode))
+	if input.ObjectLockRetainUntilDate != nil {
+		field(input.ObjectLockRetainUntilDate.UTC().Format(time.RFC3339Nano))
+	} else {
+		field("")
+	}
+	field(string(input.ObjectLockLegalHoldStatus))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// listParts lista as partes já enviadas de um envio multipart
+func (c *Client) listParts(ctx context.Context, bucket, key, uploadID string) ([]types.Part, error) {
+	input := &s3.ListPartsInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	}
+	if key := c.customerKeyBytes(); key != nil {
+		input.SSECustomerAlgorithm, input.SSECustomerKey, input.SSECustomerKeyMD5 = customerKey(key)
+	}
+
+	var parts []types.Part
+	paginator := s3.NewListPartsPaginator(c.s3, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao listar partes: %w", err)
+		}
+		parts = append(parts, page.Parts...)
+	}
+	return parts, nil
+}
+
+// ListMultipartUploads lista os envios multipart incompletos do bucket, com
+// a quantidade e o tamanho das partes já enviadas
+func (c *Client) ListMultipartUploads(ctx context.Context, bucket string) ([]models.MultipartUpload, error) {
+	own := make(map[string]bool)
+	if c.journal != nil {
+		for _, p := range c.journal.PendingUploads() {
+			if p.Bucket == bucket {
+				own[p.UploadID] = true
+			}
+		}
+	}
+
+	var uploads []models.MultipartUpload
+	paginator := s3.NewListMultipartUploadsPaginator(c.s3, &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(bucket),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao listar envios incompletos: %w", err)
+		}
+		for _, u := range page.Uploads {
+			upload := models.MultipartUpload{
+				Key:          aws.ToString(u.Key),
+				UploadID:     aws.ToString(u.UploadId),
+				Initiated:    aws.ToTime(u.Initiated),
+				StorageClass: string(u.StorageClass),
+			}
+			upload.Own = own[upload.UploadID]
+			delete(own, upload.UploadID)
+
+			// Uma falha aqui (ex: envio SSE-C sem a chave) não impede a listagem
+			if parts, err := c.listParts(ctx, bucket, upload.Key, upload.UploadID); err == nil {
+				upload.Parts = len(parts)
+				for _, p := range parts {
+					upload.Size += aws.ToInt64(p.Size)
+				}
+			} else if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			uploads = append(uploads, upload)
+		}
+	}
+
+	// Registros que não aparecem mais no bucket foram concluídos ou abortados
+	for id := range own {
+		c.journal.ForgetPendingUpload(id)
+	}
+
+	sort.Slice(uploads, func(i, j int) bool {
+		return uploads[i].Initiated.Before(uploads[j].Initiated)
+	})
+	return uploads, nil
+}
+
+// AbortMultipartUpload descarta um envio multipart e as partes já enviadas
+func (c *Client) AbortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	_, err := c.s3.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil && !isAPIError(err, "NoSuchUpload") {
+		return fmt.Errorf("falha ao abortar envio de %s: %w", key, err)
+	}
+	if c.journal != nil {
+		c.journal.ForgetPendingUpload(uploadID)
+	}
+	return nil
+}
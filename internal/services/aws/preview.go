@@ -0,0 +1,65 @@
+// s3/preview.go
+package aws
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"s3nd-files/internal/envelope"
+	"s3nd-files/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// OpenObject abre os primeiros limit bytes de bucket/key com uma leitura
+// parcial (Range), decifrando objetos cifrados no cliente. info traz o
+// tamanho total e os tipos do objeto.
+func (c *Client) OpenObject(ctx context.Context, bucket, key string, limit int64) (io.ReadCloser, models.ObjectInfo, error) {
+	info := models.ObjectInfo{Key: key}
+
+	// O trecho cifrado é um pouco maior que o original
+	out, err := c.getObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=0-%d", envelope.EncryptedPrefix(limit)-1)),
+	})
+	if isAPIError(err, "InvalidRange") {
+		// Objeto vazio
+		return io.NopCloser(strings.NewReader("")), info, nil
+	}
+	if err != nil {
+		return nil, info, err
+	}
+
+	info.Size = aws.ToInt64(out.ContentLength)
+	if _, total, ok := strings.Cut(aws.ToString(out.ContentRange), "/"); ok {
+		info.Size, _ = strconv.ParseInt(total, 10, 64)
+	}
+	info.LastModified = aws.ToTime(out.LastModified)
+	info.ContentType = aws.ToString(out.ContentType)
+	info.ContentEncoding = aws.ToString(out.ContentEncoding)
+	info.ETag = strings.Trim(aws.ToString(out.ETag), `"`)
+	info.ClientEncrypted = envelope.IsEncrypted(out.Metadata)
+	info.StorageClass = string(out.StorageClass)
+	info.Encryption = encryptionMode(out.ServerSideEncryption, out.SSECustomerAlgorithm)
+	info.KMSKeyID = aws.ToString(out.SSEKMSKeyId)
+	info.Metadata = out.Metadata
+
+	body, err := c.decryptBody(out.Body, out.Metadata)
+	if err != nil {
+		out.Body.Close()
+		return nil, info, err
+	}
+	if info.ClientEncrypted {
+		info.Size, _ = strconv.ParseInt(out.Metadata[envelope.MetaSize], 10, 64)
+	}
+	// Lê no máximo limit bytes (já decifrados) e fecha a resposta
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(body, limit), out.Body}, info, nil
+}
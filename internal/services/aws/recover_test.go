@@ -0,0 +1,140 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"s3nd-files/internal/models"
+)
+
+// fakeS3 responde às chamadas de uma cópia em partes e registra o que recebeu
+type fakeS3 struct {
+	size int64
+
+	mu         sync.Mutex
+	tagVersion string
+	tagged     string
+	ranges     []string
+	sources    []string
+	completed  bool
+	aborted    bool
+	copied     bool
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	q := r.URL.Query()
+	switch {
+	case r.Method == http.MethodHead:
+		w.Header().Set("Content-Length", fmt.Sprint(f.size))
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Header().Set("x-amz-version-id", q.Get("versionId"))
+		w.Header().Set("x-amz-meta-origem", "teste")
+	case r.Method == http.MethodGet && q.Has("tagging"):
+		f.tagVersion = q.Get("versionId")
+		fmt.Fprint(w, `<Tagging><TagSet><Tag><Key>projeto</Key><Value>x</Value></Tag></TagSet></Tagging>`)
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		f.tagged = r.Header.Get("x-amz-tagging")
+		fmt.Fprint(w, `<InitiateMultipartUploadResult><UploadId>up1</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == http.MethodPut && q.Has("partNumber"):
+		f.ranges = append(f.ranges, r.Header.Get("x-amz-copy-source-range"))
+		f.sources = append(f.sources, r.Header.Get("x-amz-copy-source"))
+		fmt.Fprintf(w, `<CopyPartResult><ETag>"p%s"</ETag></CopyPartResult>`, q.Get("partNumber"))
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		f.completed = true
+		fmt.Fprint(w, `<CompleteMultipartUploadResult><ETag>"final"</ETag></CompleteMultipartUploadResult>`)
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		f.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut && r.Header.Get("x-amz-copy-source") != "":
+		f.copied = true
+		fmt.Fprint(w, `<CopyObjectResult><ETag>"c"</ETag></CopyObjectResult>`)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := New(Config{
+		Endpoint:       server.URL,
+		Region:         "us-east-1",
+		AccessKey:      "teste",
+		SecretKey:      "teste",
+		DisableSSL:     true,
+		ForcePathStyle: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRecoverLargeObjectInParts(t *testing.T) {
+	f := &fakeS3{size: maxCopySize + 1}
+	c := newTestClient(t, f)
+
+	candidate := models.RecoveryCandidate{
+		Key:    "dados/grande.bin",
+		Target: models.ObjectVersion{Key: "dados/grande.bin", VersionID: "v1"},
+		Newer:  []models.ObjectVersion{{Key: "dados/grande.bin", VersionID: "v2", IsLatest: true}},
+	}
+	if err := c.Recover(context.Background(), "bucket", candidate); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.copied {
+		t.Error("objeto acima de 5 GiB não deve usar CopyObject")
+	}
+	if !f.completed || f.aborted {
+		t.Fatalf("envio em partes: concluído = %v, cancelado = %v", f.completed, f.aborted)
+	}
+	if f.tagVersion != "v1" || f.tagged != "projeto=x" {
+		t.Errorf("tags da versão %q = %q, esperado projeto=x da v1", f.tagVersion, f.tagged)
+	}
+
+	// As partes cobrem o objeto inteiro, em ordem e sem sobreposição
+	step := partSize(f.size)
+	want := int((f.size + step - 1) / step)
+	if len(f.ranges) != want {
+		t.Fatalf("%d partes, esperado %d", len(f.ranges), want)
+	}
+	for i, r := range f.ranges {
+		start := int64(i) * step
+		end := min(start+step, f.size) - 1
+		if r != fmt.Sprintf("bytes=%d-%d", start, end) {
+			t.Fatalf("parte %d: intervalo %q", i+1, r)
+		}
+		if !strings.HasSuffix(f.sources[i], "?versionId=v1") {
+			t.Fatalf("parte %d: origem %q sem a versão restaurada", i+1, f.sources[i])
+		}
+	}
+}
+
+func TestRecoverSmallObjectCopies(t *testing.T) {
+	f := &fakeS3{size: 10}
+	c := newTestClient(t, f)
+
+	candidate := models.RecoveryCandidate{
+		Key:    "a.txt",
+		Target: models.ObjectVersion{Key: "a.txt", VersionID: "v1"},
+		Newer:  []models.ObjectVersion{{Key: "a.txt", VersionID: "v2", IsLatest: true}},
+	}
+	if err := c.Recover(context.Background(), "bucket", candidate); err != nil {
+		t.Fatal(err)
+	}
+	if !f.copied || len(f.ranges) > 0 {
+		t.Errorf("CopyObject = %v, partes = %d; esperado só CopyObject", f.copied, len(f.ranges))
+	}
+}
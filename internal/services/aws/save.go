@@ -0,0 +1,101 @@
+// s3/save.go
+package aws
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"s3nd-files/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// SaveObject regrava bucket/key com data, desde que ainda esteja na versão
+// base (mesmo ETag). Usa escrita condicional (If-Match); se o provedor não a
+// suportar, compara o ETag antes de gravar. Cabeçalhos, classe,
+// criptografia, metadados e tags de base são mantidos.
+func (c *Client) SaveObject(ctx context.Context, bucket, key string, data []byte, base models.ObjectInfo) (models.ObjectInfo, error) {
+	if base.ClientEncrypted {
+		return base, fmt.Errorf("objetos cifrados no cliente não podem ser editados")
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:  aws.String(bucket),
+		Key:     aws.String(key),
+		Body:    bytes.NewReader(data),
+		IfMatch: aws.String(`"` + base.ETag + `"`),
+	}
+	applyPutHeaders(input, models.ObjectHeaders{
+		ContentType:        base.ContentType,
+		CacheControl:       base.CacheControl,
+		ContentEncoding:    base.ContentEncoding,
+		ContentDisposition: base.ContentDisposition,
+		Metadata:           base.Metadata,
+	})
+	if base.StorageClass != "" && base.StorageClass != "STANDARD" {
+		input.StorageClass = types.StorageClass(base.StorageClass)
+	}
+
+	enc := models.Encryption{Mode: base.Encryption, KMSKeyID: base.KMSKeyID}
+	if enc.Mode == models.SSEC {
+		enc.CustomerKey = c.customerKeyBytes()
+	}
+	if err := enc.Validate(); err != nil {
+		return base, err
+	}
+	applyPutEncryption(input, enc)
+
+	// Provedor sem suporte a tags: o objeto não tem nenhuma
+	tags, err := c.GetObjectTags(ctx, bucket, key)
+	if err != nil && !notImplemented(err) {
+		return base, err
+	}
+	if len(tags) > 0 {
+		input.Tagging = taggingHeader(tags)
+	}
+
+	out, err := c.s3.PutObject(ctx, input)
+	if notImplemented(err) {
+		// Provedor sem escrita condicional: comparar o ETag antes
+		head, headErr := c.headObject(ctx, bucket, key, "")
+		if headErr != nil {
+			return base, headErr
+		}
+		if head.ETag != base.ETag {
+			return base, models.ErrConflict
+		}
+		input.IfMatch = nil
+		input.Body = bytes.NewReader(data)
+		out, err = c.s3.PutObject(ctx, input)
+	}
+	if isAPIError(err, "PreconditionFailed", "ConditionalRequestConflict", "NoSuchKey") {
+		return base, models.ErrConflict
+	}
+	if err != nil {
+		return base, fmt.Errorf("falha ao salvar objeto: %w", err)
+	}
+
+	saved := base
+	saved.ETag = strings.Trim(aws.ToString(out.ETag), `"`)
+	saved.VersionID = aws.ToString(out.VersionId)
+	saved.Size = int64(len(data))
+	saved.LastModified = time.Now()
+	return saved, nil
+}
+
+// notImplemented indica um recurso que o provedor não suporta
+func notImplemented(err error) bool {
+	if isAPIError(err, "NotImplemented") {
+		return true
+	}
+	var respErr *smithyhttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotImplemented
+}
@@ -0,0 +1,227 @@
+// s3/sse.go
+package aws
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"s3nd-files/internal/envelope"
+	"s3nd-files/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// ErrCustomerKeyRequired indica um objeto SSE-C lido sem a chave do cliente
+var ErrCustomerKeyRequired = errors.New("o objeto está criptografado com SSE-C: carregue a chave do cliente para acessá-lo")
+
+// Encryption retorna a criptografia padrão da conexão
+func (c *Client) Encryption() models.Encryption {
+	c.sseMu.RLock()
+	defer c.sseMu.RUnlock()
+	return c.sse
+}
+
+// customerKeyBytes retorna a chave SSE-C carregada (nil se não houver)
+func (c *Client) customerKeyBytes() []byte {
+	return c.Encryption().CustomerKey
+}
+
+// SetCustomerKey define a chave SSE-C usada para ler e copiar objetos
+func (c *Client) SetCustomerKey(key []byte) error {
+	if len(key) != 32 {
+		return fmt.Errorf("SSE-C exige uma chave de 32 bytes")
+	}
+	c.sseMu.Lock()
+	defer c.sseMu.Unlock()
+	c.sse.CustomerKey = key
+	return nil
+}
+
+// customerKey retorna algoritmo, chave e MD5 no formato dos cabeçalhos SSE-C
+func customerKey(key []byte) (algorithm, encoded, md5sum *string) {
+	sum := md5.Sum(key)
+	return aws.String("AES256"),
+		aws.String(base64.StdEncoding.EncodeToString(key)),
+		aws.String(base64.StdEncoding.EncodeToString(sum[:]))
+}
+
+// applyPutEncryption preenche os cabeçalhos de criptografia de um PutObject
+func applyPutEncryption(input *s3.PutObjectInput, enc models.Encryption) {
+	switch enc.Mode {
+	case models.SSES3:
+		input.ServerSideEncryption = types.ServerSideEncryptionAes256
+	case models.SSEKMS:
+		input.ServerSideEncryption = types.ServerSideEncryptionAwsKms
+		if enc.KMSKeyID != "" {
+			input.SSEKMSKeyId = aws.String(enc.KMSKeyID)
+		}
+	case models.SSEC:
+		input.SSECustomerAlgorithm, input.SSECustomerKey, input.SSECustomerKeyMD5 = customerKey(enc.CustomerKey)
+	}
+}
+
+// needsCustomerKey verifica se o S3 recusou a leitura por falta da chave
+// SSE-C. O GET responde InvalidRequest citando a criptografia; o HEAD não
+// tem corpo, então o SDK só vê um 400 sem código (BadRequest).
+func needsCustomerKey(err error) bool {
+	var respErr *smithyhttp.ResponseError
+	if !errors.As(err, &respErr) || respErr.HTTPStatusCode() != http.StatusBadRequest {
+		return false
+	}
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "BadRequest":
+		return true
+	case "InvalidRequest", "InvalidArgument":
+		msg := strings.ToLower(apiErr.ErrorMessage())
+		return strings.Contains(msg, "server side encryption") || strings.Contains(msg, "customer")
+	}
+	return false
+}
+
+// sseError troca erros de leitura de objetos SSE-C por mensagens claras
+func (c *Client) sseError(err error, action string) error {
+	if err == nil {
+		return nil
+	}
+	key := c.customerKeyBytes()
+	if needsCustomerKey(err) && key == nil {
+		return fmt.Errorf("falha ao %s: %w", action, ErrCustomerKeyRequired)
+	}
+
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusForbidden && key != nil {
+		return fmt.Errorf("falha ao %s: acesso negado (se o objeto usa SSE-C, a chave carregada pode não ser a correta): %w", action, err)
+	}
+	return fmt.Errorf("falha ao %s: %w", action, err)
+}
+
+// getObject faz o GET e, se o objeto exigir SSE-C, repete com a chave carregada
+func (c *Client) getObject(ctx context.Context, input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	out, err := c.s3.GetObject(ctx, input)
+	if key := c.customerKeyBytes(); needsCustomerKey(err) && key != nil {
+		input.SSECustomerAlgorithm, input.SSECustomerKey, input.SSECustomerKeyMD5 = customerKey(key)
+		out, err = c.s3.GetObject(ctx, input)
+	}
+	if isAPIError(err, "InvalidObjectState") {
+		return nil, fmt.Errorf("falha ao baixar objeto: %w", ErrArchived)
+	}
+	return out, c.sseError(err, "baixar objeto")
+}
+
+// HeadObject lê os metadados de bucket/key
+func (c *Client) HeadObject(ctx context.Context, bucket, key string) (models.ObjectInfo, error) {
+	return c.headObject(ctx, bucket, key, "")
+}
+
+func (c *Client) headObject(ctx context.Context, bucket, key, versionID string) (models.ObjectInfo, error) {
+	input := &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if versionID != "" {
+		input.VersionId = aws.String(versionID)
+	}
+
+	out, err := c.s3.HeadObject(ctx, input)
+	if key := c.customerKeyBytes(); needsCustomerKey(err) && key != nil {
+		input.SSECustomerAlgorithm, input.SSECustomerKey, input.SSECustomerKeyMD5 = customerKey(key)
+		out, err = c.s3.HeadObject(ctx, input)
+	}
+	if err != nil {
+		return models.ObjectInfo{}, c.sseError(err, "ler metadados")
+	}
+
+	info := models.ObjectInfo{
+		Key:          key,
+		VersionID:    aws.ToString(out.VersionId),
+		Size:         aws.ToInt64(out.ContentLength),
+		LastModified: aws.ToTime(out.LastModified),
+		ContentType:  aws.ToString(out.ContentType),
+		ETag:         strings.Trim(aws.ToString(out.ETag), `"`),
+		StorageClass: string(out.StorageClass),
+		KMSKeyID:     aws.ToString(out.SSEKMSKeyId),
+
+		ContentEncoding:    aws.ToString(out.ContentEncoding),
+		CacheControl:       aws.ToString(out.CacheControl),
+		ContentDisposition: aws.ToString(out.ContentDisposition),
+		Encryption:         encryptionMode(out.ServerSideEncryption, out.SSECustomerAlgorithm),
+		Metadata:           out.Metadata,
+
+		ClientEncrypted: envelope.IsEncrypted(out.Metadata),
+		Restore:         models.ParseRestoreHeader(aws.ToString(out.Restore)),
+	}
+	if info.StorageClass == "" {
+		info.StorageClass = "STANDARD"
+	}
+	return info, nil
+}
+
+// encryptionMode traduz os cabeçalhos de criptografia de uma resposta
+func encryptionMode(sse types.ServerSideEncryption, customerAlgorithm *string) string {
+	switch {
+	case customerAlgorithm != nil:
+		return models.SSEC
+	case sse == types.ServerSideEncryptionAes256:
+		return models.SSES3
+	case strings.HasPrefix(string(sse), "aws:kms"):
+		return models.SSEKMS
+	}
+	return models.SSENone
+}
+
+// copyInPlace copia uma versão sobre a própria chave mantendo a
+// criptografia da origem (SSE-C usa a chave carregada nos dois lados).
+// Acima de maxCopySize a cópia é feita em partes.
+func (c *Client) copyInPlace(ctx context.Context, bucket, key, versionID string) error {
+	info, err := c.headObject(ctx, bucket, key, versionID)
+	if err != nil {
+		return err
+	}
+	if info.Size > maxCopySize {
+		return c.copyMultipart(ctx, bucket, key, bucket, key, info)
+	}
+
+	input := &s3.CopyObjectInput{
+		Bucket:     aws.String(bucket),
+		Key:        aws.String(key),
+		CopySource: aws.String(copySource(bucket, key, versionID)),
+	}
+
+	c.copyEncryption(input, info)
+
+	if _, err := c.s3.CopyObject(ctx, input); err != nil {
+		return c.sseError(err, "copiar objeto")
+	}
+	return nil
+}
+
+// copyEncryption repete na cópia a criptografia do objeto de origem
+func (c *Client) copyEncryption(input *s3.CopyObjectInput, info models.ObjectInfo) {
+	switch info.Encryption {
+	case models.SSES3:
+		input.ServerSideEncryption = types.ServerSideEncryptionAes256
+	case models.SSEKMS:
+		input.ServerSideEncryption = types.ServerSideEncryptionAwsKms
+		if info.KMSKeyID != "" {
+			input.SSEKMSKeyId = aws.String(info.KMSKeyID)
+		}
+	case models.SSEC:
+		// headObject só chega aqui com SSE-C se a chave estiver carregada
+		key := c.customerKeyBytes()
+		input.CopySourceSSECustomerAlgorithm, input.CopySourceSSECustomerKey, input.CopySourceSSECustomerKeyMD5 = customerKey(key)
+		input.SSECustomerAlgorithm, input.SSECustomerKey, input.SSECustomerKeyMD5 = customerKey(key)
+	}
+}
@@ -0,0 +1,419 @@
+// local/client.go
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"s3nd-files/internal/models"
+)
+
+// Client expõe um diretório local (ex: um NAS montado) com o mesmo modelo
+// de listagem do cliente S3: cada subpasta da raiz é tratada como um bucket
+// e os caminhos dentro dela como chaves.
+type Client struct {
+	root string
+}
+
+type Config struct {
+	Root string // Diretório raiz, ex: /mnt/nas/staging
+}
+
+func New(cfg Config) (*Client, error) {
+	if cfg.Root == "" {
+		return nil, fmt.Errorf("diretório raiz não pode ser vazio")
+	}
+
+	root, err := filepath.Abs(cfg.Root)
+	if err != nil {
+		return nil, fmt.Errorf("caminho inválido: %w", err)
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao acessar diretório raiz: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s não é um diretório", root)
+	}
+
+	return &Client{root: root}, nil
+}
+
+// Root retorna o diretório raiz usado pelo cliente
+func (c *Client) Root() string {
+	return c.root
+}
+
+func (c *Client) ListBuckets(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(c.root)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar buckets: %w", err)
+	}
+
+	var buckets []string
+	for _, e := range entries {
+		if e.IsDir() {
+			buckets = append(buckets, e.Name())
+		}
+	}
+	return buckets, nil
+}
+
+// Método adicional para testar conexão
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := os.Stat(c.root)
+	return err
+}
+
+func (c *Client) ListObjects(ctx context.Context, bucket, prefix string) ([]models.Item, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("nome do bucket não pode ser vazio")
+	}
+
+	dir, err := c.resolve(bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar objetos: %w", err)
+	}
+
+	var items []models.Item
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if isTemp(e.Name()) {
+			continue
+		}
+
+		if e.IsDir() {
+			items = append(items, models.Item{
+				Name:   e.Name() + "/",
+				Type:   models.Folder,
+				Prefix: prefix + e.Name() + "/",
+			})
+			continue
+		}
+
+		items = append(items, models.Item{
+			Name:   e.Name(),
+			Type:   models.File,
+			Prefix: prefix + e.Name(),
+		})
+	}
+
+	models.SortItems(items)
+
+	return items, nil
+}
+
+// ListObjectsPaginated lista uma página de entradas em ordem de nome.
+// O token é o nome da última entrada da página anterior.
+func (c *Client) ListObjectsPaginated(ctx context.Context, bucket, prefix, continuationToken string, maxKeys int32) ([]models.Item, string, error) {
+	if bucket == "" {
+		return nil, "", fmt.Errorf("nome do bucket não pode ser vazio")
+	}
+
+	dir, err := c.resolve(bucket, prefix)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// os.ReadDir já devolve as entradas ordenadas por nome
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, "", fmt.Errorf("falha ao listar objetos: %w", err)
+	}
+	entries = slices.DeleteFunc(entries, func(e os.DirEntry) bool { return isTemp(e.Name()) })
+
+	start := sort.Search(len(entries), func(i int) bool {
+		return entries[i].Name() > continuationToken
+	})
+	end := len(entries)
+	if maxKeys > 0 && start+int(maxKeys) < end {
+		end = start + int(maxKeys)
+	}
+
+	var items []models.Item
+	for _, e := range entries[start:end] {
+		if e.IsDir() {
+			items = append(items, models.Item{
+				Name:   e.Name() + "/",
+				Type:   models.Folder,
+				Prefix: prefix + e.Name() + "/",
+			})
+			continue
+		}
+
+		items = append(items, models.Item{
+			Name:   e.Name(),
+			Type:   models.File,
+			Prefix: prefix + e.Name(),
+		})
+	}
+
+	nextToken := ""
+	if end < len(entries) {
+		nextToken = entries[end-1].Name()
+	}
+
+	return items, nextToken, nil
+}
+
+// WalkObjects percorre recursivamente os arquivos sob o prefixo,
+// chamando fn para cada um com a chave relativa ao bucket
+func (c *Client) WalkObjects(ctx context.Context, bucket, prefix string, fn func(models.Object) error) error {
+	bucketDir, err := c.resolve(bucket, "")
+	if err != nil {
+		return err
+	}
+
+	// O prefixo pode terminar no meio de um nome (como no S3), então
+	// caminhamos a partir do diretório pai e filtramos pelas chaves
+	dir, err := c.resolve(bucket, prefix[:strings.LastIndex(prefix, "/")+1])
+	if err != nil {
+		return err
+	}
+
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || isTemp(d.Name()) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(bucketDir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		return fn(models.Object{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+			StorageClass: "LOCAL",
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("falha ao listar objetos: %w", err)
+	}
+
+	return nil
+}
+
+// UploadFile copia um arquivo local para bucket/key dentro da raiz.
+// Opções de criptografia no servidor não se aplicam a pastas locais.
+func (c *Client) UploadFile(ctx context.Context, bucket, key, srcPath string, opts models.UploadOptions) error {
+	dest, err := c.object(bucket, key)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dest); err == nil && opts.IfAbsent {
+		return fmt.Errorf("falha ao enviar arquivo: %w", models.ErrObjectExists)
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("falha ao abrir arquivo: %w", err)
+	}
+	defer src.Close()
+
+	return writeFile(ctx, dest, src)
+}
+
+// DownloadFile copia bucket/key para o caminho local dest
+func (c *Client) DownloadFile(ctx context.Context, bucket, key, dest string) error {
+	path, err := c.object(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("falha ao abrir objeto: %w", err)
+	}
+	defer src.Close()
+
+	return writeFile(ctx, dest, src)
+}
+
+// StatObjects informa quais das chaves existem em bucket
+func (c *Client) StatObjects(ctx context.Context, bucket string, keys []string) (map[string]models.Object, error) {
+	found := make(map[string]models.Object)
+	for _, key := range keys {
+		path, err := c.object(bucket, key)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		found[key] = models.Object{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		}
+	}
+	return found, nil
+}
+
+// DeleteObject remove o arquivo bucket/key
+func (c *Client) DeleteObject(ctx context.Context, bucket, key string) error {
+	path, err := c.object(bucket, key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("falha ao excluir objeto: %w", err)
+	}
+	return nil
+}
+
+// CopyObject copia o arquivo srcBucket/srcKey para dstBucket/dstKey
+func (c *Client) CopyObject(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	src, err := c.object(srcBucket, srcKey)
+	if err != nil {
+		return err
+	}
+	dest, err := c.object(dstBucket, dstKey)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("falha ao abrir objeto: %w", err)
+	}
+	defer f.Close()
+
+	return writeFile(ctx, dest, f)
+}
+
+// resolve converte bucket/key em um caminho absoluto, garantindo que
+// o resultado não escape da raiz (ex: chaves com "..")
+func (c *Client) resolve(bucket, key string) (string, error) {
+	if bucket == "" {
+		return "", fmt.Errorf("nome do bucket não pode ser vazio")
+	}
+	if strings.ContainsAny(bucket, `/\`) {
+		return "", fmt.Errorf("nome de bucket inválido: %s", bucket)
+	}
+
+	path := filepath.Join(c.root, bucket, filepath.FromSlash(key))
+	bucketDir := filepath.Join(c.root, bucket)
+	if path != bucketDir && !strings.HasPrefix(path, bucketDir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("chave inválida: %s", key)
+	}
+
+	return path, nil
+}
+
+// object resolve o arquivo de um objeto. Chaves vazias ou terminadas em
+// "/" apontariam para uma pasta (ou o próprio bucket) e são recusadas.
+func (c *Client) object(bucket, key string) (string, error) {
+	if key == "" || strings.HasSuffix(key, "/") {
+		return "", fmt.Errorf("chave inválida: %q", key)
+	}
+	if isTemp(path.Base(key)) {
+		return "", fmt.Errorf("chave reservada: %s", key)
+	}
+	return c.resolve(bucket, key)
+}
+
+// Prefixo dos arquivos temporários de writeFile, que ficam fora das listagens
+const tempPrefix = ".s3nd-"
+
+func isTemp(name string) bool {
+	return strings.HasPrefix(name, tempPrefix)
+}
+
+// writeFile grava r em dest usando um arquivo temporário no mesmo
+// diretório, para que um destino parcial nunca fique visível
+func writeFile(ctx context.Context, dest string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("falha ao criar diretório: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), tempPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("falha ao criar arquivo temporário: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, &ctxReader{ctx: ctx, r: r}); err != nil {
+		tmp.Close()
+		return fmt.Errorf("falha ao copiar arquivo: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("falha ao gravar arquivo: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), dest)
+}
+
+// ctxReader interrompe a cópia quando o contexto é cancelado
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// OpenObject abre os primeiros limit bytes de bucket/key
+func (c *Client) OpenObject(ctx context.Context, bucket, key string, limit int64) (io.ReadCloser, models.ObjectInfo, error) {
+	info := models.ObjectInfo{Key: key}
+
+	path, err := c.object(bucket, key)
+	if err != nil {
+		return nil, info, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, info, fmt.Errorf("falha ao abrir objeto: %w", err)
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, info, fmt.Errorf("falha ao abrir objeto: %w", err)
+	}
+
+	info.Size = stat.Size()
+	info.LastModified = stat.ModTime()
+	info.ContentType = mime.TypeByExtension(filepath.Ext(path))
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(f, limit), f}, info, nil
+}
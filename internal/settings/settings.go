@@ -0,0 +1,265 @@
+// settings/settings.go
+//
+// Preferências do usuário gravadas em JSON na pasta de configuração do
+// sistema (ex: ~/.config/s3nd-files/settings.json).
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"s3nd-files/internal/filter"
+	"s3nd-files/internal/headers"
+	"s3nd-files/internal/models"
+)
+
+// data é o conteúdo do arquivo de preferências
+type data struct {
+	// Classe de armazenamento padrão por "bucket/prefixo"
+	StorageClasses map[string]string `json:"storageClasses,omitempty"`
+	// Envios em partes iniciados pelo app e ainda não concluídos
+	PendingUploads []models.PendingUpload `json:"pendingUploads,omitempty"`
+	// Última política escolhida para chaves que já existem no envio
+	ConflictPolicy models.ConflictPolicy `json:"conflictPolicy,omitempty"`
+	// Filtros ao adicionar pastas locais (nil = padrão)
+	FolderFilter *filter.Rules `json:"folderFilter,omitempty"`
+	// Modelos de chave de envio por perfil de conexão, o último usado primeiro
+	KeyTemplates map[string][]string `json:"keyTemplates,omitempty"`
+	// Cabeçalhos aplicados aos envios por padrão de arquivo
+	UploadRules []headers.Rule `json:"uploadRules,omitempty"`
+}
+
+// Quantos modelos de chave guardar por perfil
+const maxKeyTemplates = 10
+
+// Store lê e grava as preferências
+type Store struct {
+	path string
+
+	mu   sync.Mutex
+	data data
+}
+
+// Open carrega as preferências do arquivo padrão. Um arquivo inexistente
+// resulta em preferências vazias.
+func Open() (*Store, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("falha ao localizar pasta de configuração: %w", err)
+	}
+	return OpenFile(filepath.Join(dir, "s3nd-files", "settings.json"))
+}
+
+// OpenFile carrega as preferências de path
+func OpenFile(path string) (*Store, error) {
+	s := &Store{path: path}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler preferências: %w", err)
+	}
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("preferências inválidas em %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Memory cria preferências que não são gravadas em disco
+func Memory() *Store {
+	return &Store{}
+}
+
+// save grava as preferências; quem chama segura s.mu
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("falha ao criar pasta de configuração: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("falha ao gravar preferências: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("falha ao gravar preferências: %w", err)
+	}
+	return nil
+}
+
+// StorageClassFor retorna a classe padrão para bucket/prefix, herdada do
+// prefixo configurado mais longo ("" quando não há)
+func (s *Store) StorageClassFor(bucket, prefix string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := bucket + "/" + prefix
+	best, class := -1, ""
+	for p, c := range s.data.StorageClasses {
+		if strings.HasPrefix(path, p) && len(p) > best {
+			best, class = len(p), c
+		}
+	}
+	return class
+}
+
+// SetStorageClass define a classe padrão de bucket/prefix; "" remove
+func (s *Store) SetStorageClass(bucket, prefix, class string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := bucket + "/" + prefix
+	if class == "" {
+		delete(s.data.StorageClasses, path)
+	} else {
+		if s.data.StorageClasses == nil {
+			s.data.StorageClasses = make(map[string]string)
+		}
+		s.data.StorageClasses[path] = class
+	}
+	return s.save()
+}
+
+// ConflictPolicy retorna a política de conflito de envio (padrão: perguntar)
+func (s *Store) ConflictPolicy() models.ConflictPolicy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.ConflictPolicy == "" {
+		return models.ConflictAsk
+	}
+	return s.data.ConflictPolicy
+}
+
+// SetConflictPolicy guarda a política de conflito de envio
+func (s *Store) SetConflictPolicy(p models.ConflictPolicy) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.ConflictPolicy = p
+	return s.save()
+}
+
+// FolderFilter retorna os filtros de pastas locais
+func (s *Store) FolderFilter() filter.Rules {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.FolderFilter == nil {
+		return filter.DefaultRules()
+	}
+	return *s.data.FolderFilter
+}
+
+// SetFolderFilter guarda os filtros de pastas locais
+func (s *Store) SetFolderFilter(rules filter.Rules) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.FolderFilter = &rules
+	return s.save()
+}
+
+// KeyTemplates retorna os modelos de chave salvos para profile, o último
+// usado primeiro
+func (s *Store) KeyTemplates(profile string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.data.KeyTemplates[profile]...)
+}
+
+// SaveKeyTemplate coloca template no topo dos modelos de profile
+func (s *Store) SaveKeyTemplate(profile, template string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	templates := []string{template}
+	for _, t := range s.data.KeyTemplates[profile] {
+		if t != template && len(templates) < maxKeyTemplates {
+			templates = append(templates, t)
+		}
+	}
+	if s.data.KeyTemplates == nil {
+		s.data.KeyTemplates = make(map[string][]string)
+	}
+	s.data.KeyTemplates[profile] = templates
+	return s.save()
+}
+
+// ForgetKeyTemplate remove template dos modelos de profile
+func (s *Store) ForgetKeyTemplate(profile, template string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var kept []string
+	for _, t := range s.data.KeyTemplates[profile] {
+		if t != template {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) == 0 {
+		delete(s.data.KeyTemplates, profile)
+	} else {
+		s.data.KeyTemplates[profile] = kept
+	}
+	return s.save()
+}
+
+// UploadRules retorna as regras de cabeçalhos dos envios
+func (s *Store) UploadRules() []headers.Rule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]headers.Rule(nil), s.data.UploadRules...)
+}
+
+// SetUploadRules guarda as regras de cabeçalhos dos envios
+func (s *Store) SetUploadRules(rules []headers.Rule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.UploadRules = rules
+	return s.save()
+}
+
+// PendingUploads retorna os envios em partes registrados
+func (s *Store) PendingUploads() []models.PendingUpload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.PendingUpload(nil), s.data.PendingUploads...)
+}
+
+// SavePendingUpload registra um envio em partes iniciado pelo app
+func (s *Store) SavePendingUpload(p models.PendingUpload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.PendingUploads = append(s.data.PendingUploads, p)
+	return s.save()
+}
+
+// ForgetPendingUpload remove o registro de um envio concluído ou abortado
+func (s *Store) ForgetPendingUpload(uploadID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.data.PendingUploads[:0]
+	for _, p := range s.data.PendingUploads {
+		if p.UploadID != uploadID {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) == len(s.data.PendingUploads) {
+		return nil
+	}
+	s.data.PendingUploads = kept
+	return s.save()
+}
@@ -0,0 +1,265 @@
+// ui/archive.go
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Intervalo entre consultas enquanto uma restauração está em andamento
+const restoreWatchInterval = 5 * time.Minute
+
+// storageClassSuffix descreve a classe e a restauração de um arquivo na listagem
+func storageClassSuffix(item models.Item) string {
+	if item.Type != models.File || item.StorageClass == "" || item.StorageClass == "STANDARD" {
+		return ""
+	}
+	if !models.IsArchiveClass(item.StorageClass) {
+		return "  [" + item.StorageClass + "]"
+	}
+
+	switch item.Restore.State {
+	case models.RestoreInProgress:
+		return "  [" + item.StorageClass + " · restaurando]"
+	case models.RestoreDone:
+		return "  [" + item.StorageClass + " · restaurado]"
+	default:
+		return "  [" + item.StorageClass + " · arquivado]"
+	}
+}
+
+// formatRestoreStatus descreve o estado de restauração para diálogos
+func formatRestoreStatus(status models.RestoreStatus) string {
+	switch status.State {
+	case models.RestoreInProgress:
+		return "Restauração em andamento"
+	case models.RestoreDone:
+		if status.Expiry.IsZero() {
+			return "Restaurado"
+		}
+		return "Restaurado até " + status.Expiry.Local().Format("2006-01-02 15:04")
+	default:
+		return "Arquivado (não restaurado)"
+	}
+}
+
+// Restaurações observadas por watchRestore, por bucket/chave
+var (
+	restoreWatchesMu sync.Mutex
+	restoreWatches   = make(map[string]*restoreWatch)
+)
+
+type restoreWatch struct {
+	cancel context.CancelFunc
+}
+
+func watchingRestore(bucket, key string) bool {
+	restoreWatchesMu.Lock()
+	defer restoreWatchesMu.Unlock()
+	return restoreWatches[bucket+"/"+key] != nil
+}
+
+// stopRestoreWatch cancela a observação de bucket/key, se houver
+func stopRestoreWatch(bucket, key string) {
+	restoreWatchesMu.Lock()
+	defer restoreWatchesMu.Unlock()
+	if rw := restoreWatches[bucket+"/"+key]; rw != nil {
+		rw.cancel()
+		delete(restoreWatches, bucket+"/"+key)
+	}
+}
+
+// showRestoreDialog mostra o estado de um objeto arquivado e permite pedir a
+// restauração ou, se já restaurado, baixá-lo
+func showRestoreDialog(w fyne.Window, client storageBackend, bucket string, item models.Item) {
+	a, ok := client.(archiver)
+	if !ok {
+		showDownloadDialog(w, client, bucket, item)
+		return
+	}
+
+	stater, ok := client.(objectStater)
+	if !ok {
+		showRestoreStatus(w, client, a, bucket, item, item.Restore)
+		return
+	}
+
+	// A listagem pode estar desatualizada
+	go func() {
+		status := item.Restore
+		if info, err := stater.HeadObject(context.Background(), bucket, item.Prefix); err == nil {
+			status = info.Restore
+		}
+		runOnUIThread(func() {
+			showRestoreStatus(w, client, a, bucket, item, status)
+		})
+	}()
+}
+
+func showRestoreStatus(w fyne.Window, client storageBackend, a archiver, bucket string, item models.Item, status models.RestoreStatus) {
+	header := widget.NewLabel(fmt.Sprintf("%s\nClasse: %s\n%s",
+		item.Prefix, item.StorageClass, formatRestoreStatus(status)))
+
+	if status.State == models.RestoreDone {
+		stopRestoreWatch(bucket, item.Prefix)
+		dialog.ShowCustomConfirm("Objeto arquivado", "Baixar", "Fechar", header, func(download bool) {
+			if download {
+				showDownloadDialog(w, client, bucket, item)
+			}
+		}, w)
+		return
+	}
+	if status.State == models.RestoreInProgress {
+		if watchingRestore(bucket, item.Prefix) {
+			dialog.ShowCustomConfirm("Objeto arquivado", "Parar de avisar", "Fechar", header, func(stop bool) {
+				if stop {
+					stopRestoreWatch(bucket, item.Prefix)
+				}
+			}, w)
+			return
+		}
+		dialog.ShowCustomConfirm("Objeto arquivado", "Avisar quando concluir", "Fechar", header, func(watch bool) {
+			if watch {
+				watchRestore(w, client, bucket, item)
+			}
+		}, w)
+		return
+	}
+
+	tierSelect := widget.NewSelect(models.RestoreTiers, nil)
+	tierSelect.SetSelected("Standard")
+	daysEntry := widget.NewEntry()
+	daysEntry.SetText("7")
+
+	hint := "Standard: 3–5 h (Deep Archive: até 12 h). Bulk: mais barato, 5–12 h (até 48 h).\n" +
+		"Expedited: 1–5 min, indisponível para Deep Archive."
+
+	content := container.NewVBox(
+		header,
+		widget.NewForm(
+			widget.NewFormItem("Velocidade", tierSelect),
+			widget.NewFormItem("Manter por (dias)", daysEntry),
+		),
+		widget.NewLabel(hint),
+	)
+
+	dialog.ShowCustomConfirm("Restaurar objeto arquivado", "Restaurar", "Fechar", content, func(restore bool) {
+		if !restore {
+			return
+		}
+		days, err := strconv.Atoi(strings.TrimSpace(daysEntry.Text))
+		if err != nil || days < 1 {
+			dialog.ShowError(fmt.Errorf("informe um número de dias válido"), w)
+			return
+		}
+
+		go func() {
+			err := a.RestoreObject(context.Background(), bucket, item.Prefix, int32(days), tierSelect.Selected)
+			runOnUIThread(func() {
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				dialog.ShowConfirm("Restauração solicitada",
+					"A restauração foi solicitada. Avisar quando o objeto puder ser baixado?",
+					func(watch bool) {
+						if watch {
+							watchRestore(w, client, bucket, item)
+						}
+					}, w)
+			})
+		}()
+	}, w)
+}
+
+// watchRestore consulta o objeto periodicamente e oferece o download quando
+// a restauração terminar. A observação é cancelada por stopRestoreWatch
+// (ex: "Parar de avisar" no diálogo do objeto).
+func watchRestore(w fyne.Window, client storageBackend, bucket string, item models.Item) {
+	stater, ok := client.(objectStater)
+	if !ok {
+		return
+	}
+
+	id := bucket + "/" + item.Prefix
+	ctx, cancel := context.WithCancel(context.Background())
+	rw := &restoreWatch{cancel: cancel}
+
+	restoreWatchesMu.Lock()
+	if prev := restoreWatches[id]; prev != nil {
+		prev.cancel()
+	}
+	restoreWatches[id] = rw
+	restoreWatchesMu.Unlock()
+
+	go func() {
+		defer func() {
+			cancel()
+			restoreWatchesMu.Lock()
+			if restoreWatches[id] == rw {
+				delete(restoreWatches, id)
+			}
+			restoreWatchesMu.Unlock()
+		}()
+
+		ticker := time.NewTicker(restoreWatchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			info, err := stater.HeadObject(ctx, bucket, item.Prefix)
+			if err != nil || info.Restore.State != models.RestoreDone {
+				continue
+			}
+
+			runOnUIThread(func() {
+				fyne.CurrentApp().SendNotification(fyne.NewNotification(
+					"Restauração concluída", item.Prefix))
+				dialog.ShowConfirm("Restauração concluída",
+					fmt.Sprintf("%s/%s já pode ser baixado.\n\nBaixar agora?", bucket, item.Prefix),
+					func(download bool) {
+						if download {
+							showDownloadDialog(w, client, bucket, item)
+						}
+					}, w)
+			})
+			return
+		}
+	}()
+}
+
+// storageClassFormItems monta a escolha de classe de armazenamento do upload,
+// já com o padrão do prefixo. O retorno lê a classe e se ela deve virar padrão.
+func storageClassFormItems(initial string) ([]*widget.FormItem, func() (string, bool)) {
+	classSelect := widget.NewSelect(models.UploadStorageClasses, nil)
+	if initial == "" {
+		initial = "STANDARD"
+	}
+	classSelect.SetSelected(initial)
+
+	defaultCheck := widget.NewCheck("Usar como padrão para esta pasta", nil)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Classe", classSelect),
+		widget.NewFormItem("", defaultCheck),
+	}
+	return items, func() (string, bool) {
+		return classSelect.Selected, defaultCheck.Checked
+	}
+}
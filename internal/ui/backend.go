@@ -0,0 +1,148 @@
+// ui/backend.go
+package ui
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"s3nd-files/internal/envelope"
+	"s3nd-files/internal/models"
+	"s3nd-files/internal/services/aws"
+	"s3nd-files/internal/services/local"
+)
+
+// storageBackend é o que o painel da direita precisa para navegar,
+// enviar e baixar arquivos. Tanto o cliente S3 quanto o de sistema de
+// arquivos local implementam esta interface.
+type storageBackend interface {
+	ListBuckets(ctx context.Context) ([]string, error)
+	ListObjects(ctx context.Context, bucket, prefix string) ([]models.Item, error)
+	ListObjectsPaginated(ctx context.Context, bucket, prefix, continuationToken string, maxKeys int32) ([]models.Item, string, error)
+	WalkObjects(ctx context.Context, bucket, prefix string, fn func(models.Object) error) error
+	UploadFile(ctx context.Context, bucket, key, filepath string, opts models.UploadOptions) error
+	DownloadFile(ctx context.Context, bucket, key, dest string) error
+	DeleteObject(ctx context.Context, bucket, key string) error
+}
+
+// Recursos que só alguns backends oferecem são verificados com type assertion
+
+// presigner gera links temporários de download
+type presigner interface {
+	PresignGetObject(ctx context.Context, bucket, key string, expires time.Duration) (string, error)
+}
+
+// tagReader lê as tags de um objeto
+type tagReader interface {
+	GetObjectTags(ctx context.Context, bucket, key string) (map[string]string, error)
+}
+
+// tagWriter grava as tags de um objeto
+type tagWriter interface {
+	PutObjectTags(ctx context.Context, bucket, key string, tags map[string]string) error
+}
+
+// versioner navega e manipula versões de objetos
+type versioner interface {
+	ListObjectVersions(ctx context.Context, bucket, key string) ([]models.ObjectVersion, error)
+	DownloadFileVersion(ctx context.Context, bucket, key, versionID, dest string) error
+	RestoreVersion(ctx context.Context, bucket, key, versionID string) error
+	DeleteVersion(ctx context.Context, bucket, key, versionID string) error
+}
+
+// recoverer encontra e recupera objetos excluídos em buckets versionados
+type recoverer interface {
+	WalkObjectVersions(ctx context.Context, bucket, prefix string, fn func(models.ObjectVersion) error) error
+	Recover(ctx context.Context, bucket string, candidate models.RecoveryCandidate) error
+}
+
+// objectStater lê os metadados de um objeto
+type objectStater interface {
+	HeadObject(ctx context.Context, bucket, key string) (models.ObjectInfo, error)
+}
+
+// sseConfigurer expõe a criptografia padrão da conexão e a chave SSE-C
+type sseConfigurer interface {
+	Encryption() models.Encryption
+	SetCustomerKey(key []byte) error
+}
+
+// clientEncrypter cifra e decifra objetos no cliente com uma chave mestra
+type clientEncrypter interface {
+	SetMasterKey(m *envelope.MasterKey)
+	HasMasterKey() bool
+}
+
+// archiver restaura objetos de classes arquivadas (GLACIER, DEEP_ARCHIVE)
+type archiver interface {
+	RestoreObject(ctx context.Context, bucket, key string, days int32, tier string) error
+}
+
+// objectLocker lê e altera a retenção e o legal hold do Object Lock
+type objectLocker interface {
+	GetObjectLock(ctx context.Context, bucket, key string) (models.ObjectLock, error)
+	PutObjectRetention(ctx context.Context, bucket, key string, retention models.Retention, bypassGovernance bool) error
+	PutObjectLegalHold(ctx context.Context, bucket, key string, on bool) error
+}
+
+// multipartManager lista e aborta envios multipart incompletos
+type multipartManager interface {
+	ListMultipartUploads(ctx context.Context, bucket string) ([]models.MultipartUpload, error)
+	AbortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error
+}
+
+// objectReader lê o início de um objeto (pré-visualização)
+type objectReader interface {
+	OpenObject(ctx context.Context, bucket, key string, limit int64) (io.ReadCloser, models.ObjectInfo, error)
+}
+
+// objectSaver regrava um objeto somente se ele não mudou desde base
+type objectSaver interface {
+	SaveObject(ctx context.Context, bucket, key string, data []byte, base models.ObjectInfo) (models.ObjectInfo, error)
+}
+
+// objectLookup confere quais chaves já existem (conflitos de envio)
+type objectLookup interface {
+	StatObjects(ctx context.Context, bucket string, keys []string) (map[string]models.Object, error)
+}
+
+// objectCopier copia objetos no servidor, sem baixá-los
+type objectCopier interface {
+	CopyObject(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error
+}
+
+// bucketSettings lê e altera configurações do bucket
+type bucketSettings interface {
+	GetBucketVersioning(ctx context.Context, bucket string) (string, error)
+	SetBucketVersioning(ctx context.Context, bucket string, enabled bool) error
+	GetLifecycleRules(ctx context.Context, bucket string) ([]models.LifecycleRule, error)
+	PutLifecycleRules(ctx context.Context, bucket string, rules []models.LifecycleRule) error
+	GetBucketPolicy(ctx context.Context, bucket string) (string, error)
+	PutBucketPolicy(ctx context.Context, bucket, policy string) error
+	GetCORSRules(ctx context.Context, bucket string) ([]models.CORSRule, error)
+	PutCORSRules(ctx context.Context, bucket string, rules []models.CORSRule) error
+}
+
+var (
+	_ storageBackend   = (*aws.Client)(nil)
+	_ storageBackend   = (*local.Client)(nil)
+	_ presigner        = (*aws.Client)(nil)
+	_ tagReader        = (*aws.Client)(nil)
+	_ tagWriter        = (*aws.Client)(nil)
+	_ versioner        = (*aws.Client)(nil)
+	_ recoverer        = (*aws.Client)(nil)
+	_ bucketSettings   = (*aws.Client)(nil)
+	_ objectStater     = (*aws.Client)(nil)
+	_ sseConfigurer    = (*aws.Client)(nil)
+	_ clientEncrypter  = (*aws.Client)(nil)
+	_ archiver         = (*aws.Client)(nil)
+	_ objectLocker     = (*aws.Client)(nil)
+	_ multipartManager = (*aws.Client)(nil)
+	_ objectReader     = (*aws.Client)(nil)
+	_ objectReader     = (*local.Client)(nil)
+	_ objectSaver      = (*aws.Client)(nil)
+	_ objectCopier     = (*aws.Client)(nil)
+	_ objectCopier     = (*local.Client)(nil)
+	_ objectLookup     = (*aws.Client)(nil)
+	_ objectLookup     = (*local.Client)(nil)
+)
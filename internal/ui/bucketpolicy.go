@@ -0,0 +1,283 @@
+// ui/bucketpolicy.go
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"s3nd-files/internal/diff"
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// jsonTemplate é um modelo pronto que pode ser mesclado ao documento atual
+type jsonTemplate struct {
+	Name  string
+	Apply func(current string) (string, error)
+}
+
+// jsonConfigTab é um editor JSON genérico com modelos, validação e
+// visualização das diferenças antes de salvar
+func jsonConfigTab(w fyne.Window, load func() (string, error), validate, save func(string) error, templates []jsonTemplate) fyne.CanvasObject {
+	var remote string
+
+	editor := widget.NewMultiLineEntry()
+	editor.TextStyle = fyne.TextStyle{Monospace: true}
+	status := widget.NewLabel("Carregando...")
+
+	reload := func() {
+		go func() {
+			text, err := load()
+			runOnUIThread(func() {
+				if err != nil {
+					status.SetText("Falha ao carregar")
+					dialog.ShowError(err, w)
+					return
+				}
+				remote = text
+				editor.SetText(text)
+				if text == "" {
+					status.SetText("Nenhuma configuração (vazio remove ao salvar)")
+				} else {
+					status.SetText("Carregado")
+				}
+			})
+		}()
+	}
+
+	names := make([]string, 0, len(templates))
+	for _, t := range templates {
+		names = append(names, t.Name)
+	}
+	var templateSelect *widget.Select
+	templateSelect = widget.NewSelect(names, func(name string) {
+		if name == "" {
+			return
+		}
+		defer templateSelect.ClearSelected()
+		for _, t := range templates {
+			if t.Name != name {
+				continue
+			}
+			text, err := t.Apply(editor.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			editor.SetText(text)
+		}
+	})
+	templateSelect.PlaceHolder = "Inserir modelo..."
+
+	validateBtn := widget.NewButton("Validar", func() {
+		if err := validate(editor.Text); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		dialog.ShowInformation("Validar", "Configuração válida", w)
+	})
+
+	formatBtn := widget.NewButton("Formatar", func() {
+		editor.SetText(models.FormatJSON(editor.Text))
+	})
+
+	var saveBtn *widget.Button
+	saveBtn = widget.NewButton("Revisar e salvar", func() {
+		text := editor.Text
+		if err := validate(text); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		lines := diff.Lines(models.FormatJSON(remote), models.FormatJSON(text))
+		if !diff.Changed(lines) {
+			dialog.ShowInformation("Salvar", "Nenhuma alteração", w)
+			return
+		}
+
+		diffLabel := widget.NewLabel(diff.Format(lines, 3))
+		diffLabel.TextStyle = fyne.TextStyle{Monospace: true}
+		scroll := container.NewScroll(diffLabel)
+		scroll.SetMinSize(fyne.NewSize(600, 360))
+
+		dialog.ShowCustomConfirm("Alterações", "Aplicar", "Cancelar", scroll, func(apply bool) {
+			if !apply {
+				return
+			}
+			saveBtn.Disable()
+			go func() {
+				err := save(text)
+				runOnUIThread(func() {
+					saveBtn.Enable()
+					if err != nil {
+						dialog.ShowError(err, w)
+						return
+					}
+					reload()
+				})
+			}()
+		}, w)
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	reload()
+
+	return container.NewBorder(
+		container.NewBorder(nil, nil, nil, container.NewHBox(formatBtn, validateBtn), templateSelect),
+		container.NewBorder(nil, nil, status, container.NewHBox(widget.NewButton("Recarregar", reload), saveBtn)),
+		nil, nil,
+		editor,
+	)
+}
+
+// policyTab edita a política do bucket
+func policyTab(w fyne.Window, settings bucketSettings, bucket string) fyne.CanvasObject {
+	ctx := context.Background()
+
+	validate := func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		return models.ValidateBucketPolicy(text)
+	}
+
+	return jsonConfigTab(w,
+		func() (string, error) {
+			policy, err := settings.GetBucketPolicy(ctx, bucket)
+			if err != nil || policy == "" {
+				return policy, err
+			}
+			return models.FormatJSON(policy), nil
+		},
+		validate,
+		func(text string) error { return settings.PutBucketPolicy(ctx, bucket, text) },
+		policyTemplates(bucket),
+	)
+}
+
+// corsTab edita as regras CORS do bucket
+func corsTab(w fyne.Window, settings bucketSettings, bucket string) fyne.CanvasObject {
+	ctx := context.Background()
+
+	return jsonConfigTab(w,
+		func() (string, error) {
+			rules, err := settings.GetCORSRules(ctx, bucket)
+			if err != nil || len(rules) == 0 {
+				return "", err
+			}
+			data, _ := json.MarshalIndent(rules, "", "  ")
+			return string(data), nil
+		},
+		func(text string) error {
+			_, err := parseCORSRules(text)
+			return err
+		},
+		func(text string) error {
+			rules, err := parseCORSRules(text)
+			if err != nil {
+				return err
+			}
+			return settings.PutCORSRules(ctx, bucket, rules)
+		},
+		[]jsonTemplate{{
+			Name: "Permitir uma origem web",
+			Apply: func(current string) (string, error) {
+				rules, err := parseCORSRules(current)
+				if err != nil {
+					return "", err
+				}
+				rules = append(rules, models.CORSRule{
+					AllowedOrigins: []string{"https://www.exemplo.com"},
+					AllowedMethods: []string{"GET", "HEAD"},
+					AllowedHeaders: []string{"*"},
+					ExposeHeaders:  []string{"ETag"},
+					MaxAgeSeconds:  3000,
+				})
+				data, _ := json.MarshalIndent(rules, "", "  ")
+				return string(data), nil
+			},
+		}},
+	)
+}
+
+// parseCORSRules lê e valida a lista de regras CORS em JSON (vazio = nenhuma)
+func parseCORSRules(text string) ([]models.CORSRule, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	var rules []models.CORSRule
+	dec := json.NewDecoder(bytes.NewReader([]byte(text)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("JSON inválido: %w", err)
+	}
+	if err := models.ValidateCORSRules(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// policyTemplates retorna modelos de statements para o bucket informado
+func policyTemplates(bucket string) []jsonTemplate {
+	publicRead := map[string]any{
+		"Sid":       "LeituraPublicaPrefixo",
+		"Effect":    "Allow",
+		"Principal": "*",
+		"Action":    "s3:GetObject",
+		"Resource":  fmt.Sprintf("arn:aws:s3:::%s/publico/*", bucket),
+	}
+	denyInsecure := map[string]any{
+		"Sid":       "NegarSemTLS",
+		"Effect":    "Deny",
+		"Principal": "*",
+		"Action":    "s3:*",
+		"Resource": []string{
+			fmt.Sprintf("arn:aws:s3:::%s", bucket),
+			fmt.Sprintf("arn:aws:s3:::%s/*", bucket),
+		},
+		"Condition": map[string]any{
+			"Bool": map[string]string{"aws:SecureTransport": "false"},
+		},
+	}
+
+	return []jsonTemplate{
+		{Name: "Leitura pública em um prefixo", Apply: appendPolicyStatement(publicRead)},
+		{Name: "Negar acesso sem TLS", Apply: appendPolicyStatement(denyInsecure)},
+	}
+}
+
+// appendPolicyStatement acrescenta um statement à política atual,
+// criando o documento se ele estiver vazio
+func appendPolicyStatement(statement map[string]any) func(string) (string, error) {
+	return func(current string) (string, error) {
+		doc := map[string]any{"Version": "2012-10-17"}
+		if strings.TrimSpace(current) != "" {
+			if err := json.Unmarshal([]byte(current), &doc); err != nil {
+				return "", fmt.Errorf("corrija o JSON atual antes de inserir um modelo: %w", err)
+			}
+		}
+
+		var statements []any
+		switch st := doc["Statement"].(type) {
+		case []any:
+			statements = st
+		case map[string]any:
+			statements = []any{st}
+		}
+		doc["Statement"] = append(statements, statement)
+
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
@@ -0,0 +1,448 @@
+// ui/bucketsettings.go
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showBucketSettingsWindow abre as configurações do bucket em abas
+func showBucketSettingsWindow(w fyne.Window, client storageBackend, bucket string) {
+	settings, ok := client.(bucketSettings)
+	if !ok {
+		dialog.ShowInformation("Configurações do bucket",
+			"Este backend não suporta configurações de bucket", w)
+		return
+	}
+
+	bw := fyne.CurrentApp().NewWindow(fmt.Sprintf("Configurações de %s", bucket))
+	bw.Resize(fyne.NewSize(820, 600))
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem("Versionamento", versioningTab(bw, settings, bucket)),
+		container.NewTabItem("Ciclo de vida", lifecycleTab(bw, settings, bucket)),
+		container.NewTabItem("Política", policyTab(bw, settings, bucket)),
+		container.NewTabItem("CORS", corsTab(bw, settings, bucket)),
+	)
+	if m, ok := client.(multipartManager); ok {
+		tabs.Append(container.NewTabItem("Envios incompletos", multipartTab(bw, m, bucket)))
+	}
+
+	bw.SetContent(tabs)
+	bw.Show()
+}
+
+// versioningTab mostra e altera o estado do versionamento do bucket
+func versioningTab(w fyne.Window, settings bucketSettings, bucket string) fyne.CanvasObject {
+	status := widget.NewLabel("Carregando...")
+
+	reload := func() {
+		go func() {
+			state, err := settings.GetBucketVersioning(context.Background(), bucket)
+			runOnUIThread(func() {
+				if err != nil {
+					status.SetText("Falha ao ler versionamento")
+					dialog.ShowError(err, w)
+					return
+				}
+				switch state {
+				case "Enabled":
+					status.SetText("Versionamento: ativado")
+				case "Suspended":
+					status.SetText("Versionamento: suspenso")
+				default:
+					status.SetText("Versionamento: nunca ativado")
+				}
+			})
+		}()
+	}
+
+	var enableBtn, suspendBtn *widget.Button
+	set := func(enabled bool) {
+		action := "Suspender"
+		if enabled {
+			action = "Ativar"
+		}
+		dialog.ShowConfirm(action+" versionamento",
+			fmt.Sprintf("%s o versionamento de %s?", action, bucket),
+			func(confirm bool) {
+				if !confirm {
+					return
+				}
+				enableBtn.Disable()
+				suspendBtn.Disable()
+				go func() {
+					err := settings.SetBucketVersioning(context.Background(), bucket, enabled)
+					runOnUIThread(func() {
+						enableBtn.Enable()
+						suspendBtn.Enable()
+						if err != nil {
+							dialog.ShowError(err, w)
+							return
+						}
+						reload()
+					})
+				}()
+			}, w)
+	}
+
+	enableBtn = widget.NewButton("Ativar", func() { set(true) })
+	suspendBtn = widget.NewButton("Suspender", func() { set(false) })
+
+	reload()
+
+	return container.NewVBox(
+		status,
+		container.NewHBox(enableBtn, suspendBtn),
+		widget.NewLabel("Depois de ativado, o versionamento só pode ser suspenso, nunca desativado.\n"+
+			"Versões existentes são mantidas ao suspender."),
+	)
+}
+
+// lifecycleTab edita as regras de ciclo de vida em formulário ou JSON
+func lifecycleTab(w fyne.Window, settings bucketSettings, bucket string) fyne.CanvasObject {
+	var (
+		rules    []models.LifecycleRule
+		selected = -1
+		loading  bool
+		// Regras como vieram do bucket, para ligar as editadas às originais
+		loaded []models.LifecycleRule
+		// Campo do formulário com valor inválido (não copiado para a regra)
+		formErr error
+	)
+
+	// Formulário
+	idEntry := widget.NewEntry()
+	prefixEntry := widget.NewEntry()
+	prefixEntry.SetPlaceHolder("vazio = bucket inteiro")
+	enabledCheck := widget.NewCheck("Ativa", nil)
+	expirationEntry := widget.NewEntry()
+	expirationEntry.SetPlaceHolder("dias (vazio = não expirar)")
+	noncurrentEntry := widget.NewEntry()
+	noncurrentEntry.SetPlaceHolder("dias (vazio = manter)")
+	abortEntry := widget.NewEntry()
+	abortEntry.SetPlaceHolder("dias (vazio = nunca abortar)")
+	transitionsEntry := widget.NewEntry()
+	transitionsEntry.SetPlaceHolder("30:STANDARD_IA, 90:GLACIER")
+
+	unsupportedLabel := widget.NewLabel("")
+	unsupportedLabel.Wrapping = fyne.TextWrapWord
+	unsupportedLabel.Importance = widget.WarningImportance
+	unsupportedLabel.Hide()
+	formEntries := []*widget.Entry{idEntry, prefixEntry, expirationEntry, noncurrentEntry, abortEntry, transitionsEntry}
+
+	ruleForm := widget.NewForm(
+		widget.NewFormItem("", unsupportedLabel),
+		widget.NewFormItem("ID", idEntry),
+		widget.NewFormItem("Prefixo", prefixEntry),
+		widget.NewFormItem("", enabledCheck),
+		widget.NewFormItem("Expirar após", expirationEntry),
+		widget.NewFormItem("Expirar versões antigas", noncurrentEntry),
+		widget.NewFormItem("Transições", transitionsEntry),
+		widget.NewFormItem("Abortar multipart", abortEntry),
+	)
+	ruleForm.Hide()
+
+	ruleList := widget.NewList(
+		func() int { return len(rules) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id < 0 || id >= len(rules) {
+				return
+			}
+			r := rules[id]
+			text := r.ID
+			if !r.Enabled {
+				text += " (inativa)"
+			}
+			if r.Unsupported != "" {
+				text += " 🔒"
+			}
+			obj.(*widget.Label).SetText(text)
+		},
+	)
+
+	// Copiar os campos do formulário para a regra selecionada
+	syncForm := func() {
+		if loading || selected < 0 || selected >= len(rules) {
+			return
+		}
+		r := rules[selected]
+		r.ID = strings.TrimSpace(idEntry.Text)
+		r.Prefix = prefixEntry.Text
+		r.Enabled = enabledCheck.Checked
+		var errs [4]error
+		r.ExpirationDays, errs[0] = parseDays(expirationEntry.Text)
+		r.NoncurrentExpirationDays, errs[1] = parseDays(noncurrentEntry.Text)
+		r.AbortMultipartDays, errs[2] = parseDays(abortEntry.Text)
+		r.Transitions, errs[3] = parseTransitions(transitionsEntry.Text)
+		if formErr = errors.Join(errs[:]...); formErr != nil {
+			return
+		}
+		rules[selected] = r
+		ruleList.RefreshItem(selected)
+	}
+	for _, e := range formEntries {
+		e.OnChanged = func(string) { syncForm() }
+	}
+	enabledCheck.OnChanged = func(bool) { syncForm() }
+
+	ruleList.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		r := rules[id]
+		loading = true
+		idEntry.SetText(r.ID)
+		prefixEntry.SetText(r.Prefix)
+		enabledCheck.SetChecked(r.Enabled)
+		expirationEntry.SetText(formatDays(r.ExpirationDays))
+		noncurrentEntry.SetText(formatDays(r.NoncurrentExpirationDays))
+		abortEntry.SetText(formatDays(r.AbortMultipartDays))
+		transitionsEntry.SetText(formatTransitions(r.Transitions))
+		loading = false
+		formErr = nil
+
+		// Regras que o formulário não representa ficam só para leitura
+		for _, e := range formEntries {
+			if r.Unsupported != "" {
+				e.Disable()
+			} else {
+				e.Enable()
+			}
+		}
+		if r.Unsupported != "" {
+			enabledCheck.Disable()
+			unsupportedLabel.SetText("Regra só para leitura (" + r.Unsupported + "). Ela será mantida como está ao salvar.")
+			unsupportedLabel.Show()
+		} else {
+			enabledCheck.Enable()
+			unsupportedLabel.Hide()
+		}
+		ruleForm.Show()
+	}
+	ruleList.OnUnselected = func(widget.ListItemID) {
+		selected = -1
+		formErr = nil
+		ruleForm.Hide()
+	}
+
+	addBtn := widget.NewButton("Nova regra", func() {
+		rules = append(rules, models.LifecycleRule{
+			ID:      fmt.Sprintf("regra-%d", len(rules)+1),
+			Enabled: true,
+		})
+		ruleList.Refresh()
+		ruleList.Select(len(rules) - 1)
+	})
+	removeBtn := widget.NewButton("Remover regra", func() {
+		if selected < 0 || selected >= len(rules) {
+			return
+		}
+		rules = append(rules[:selected], rules[selected+1:]...)
+		ruleList.UnselectAll()
+		ruleList.Refresh()
+	})
+
+	formView := container.NewHSplit(
+		container.NewBorder(nil, container.NewHBox(addBtn, removeBtn), nil, nil, ruleList),
+		container.NewVScroll(ruleForm),
+	)
+	formView.SetOffset(0.3)
+
+	// JSON
+	jsonEntry := widget.NewMultiLineEntry()
+	jsonEntry.TextStyle = fyne.TextStyle{Monospace: true}
+
+	toJSON := func() {
+		data, _ := json.MarshalIndent(rules, "", "  ")
+		jsonEntry.SetText(string(data))
+	}
+	fromJSON := func() ([]models.LifecycleRule, error) {
+		var parsed []models.LifecycleRule
+		dec := json.NewDecoder(bytes.NewReader([]byte(jsonEntry.Text)))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&parsed); err != nil {
+			return nil, fmt.Errorf("JSON inválido: %w", err)
+		}
+		if err := models.KeepOriginals(parsed, loaded); err != nil {
+			return nil, err
+		}
+		if err := models.ValidateLifecycleRules(parsed); err != nil {
+			return nil, err
+		}
+		return parsed, nil
+	}
+
+	validateBtn := widget.NewButton("Validar", func() {
+		if _, err := fromJSON(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		dialog.ShowInformation("Validar", "Regras válidas", w)
+	})
+	jsonView := container.NewBorder(nil, container.NewHBox(validateBtn), nil, nil, jsonEntry)
+
+	modeTabs := container.NewAppTabs(
+		container.NewTabItem("Formulário", formView),
+		container.NewTabItem("JSON", jsonView),
+	)
+	reverting := false
+	modeTabs.OnSelected = func(tab *container.TabItem) {
+		if reverting {
+			return
+		}
+		if tab.Content == jsonView {
+			toJSON()
+			return
+		}
+		parsed, err := fromJSON()
+		if err != nil {
+			// Manter no editor JSON até que seja corrigido
+			reverting = true
+			modeTabs.Select(modeTabs.Items[1])
+			reverting = false
+			dialog.ShowError(err, w)
+			return
+		}
+		rules = parsed
+		ruleList.UnselectAll()
+		ruleList.Refresh()
+	}
+
+	status := widget.NewLabel("Carregando regras...")
+
+	reload := func() {
+		go func() {
+			out, err := settings.GetLifecycleRules(context.Background(), bucket)
+			runOnUIThread(func() {
+				if err != nil {
+					status.SetText("Falha ao ler ciclo de vida")
+					dialog.ShowError(err, w)
+					return
+				}
+				rules, loaded = out, append([]models.LifecycleRule(nil), out...)
+				ruleList.UnselectAll()
+				ruleList.Refresh()
+				toJSON()
+				status.SetText(fmt.Sprintf("%d regra(s)", len(rules)))
+			})
+		}()
+	}
+
+	var saveBtn *widget.Button
+	saveBtn = widget.NewButton("Salvar", func() {
+		if formErr != nil {
+			dialog.ShowError(formErr, w)
+			return
+		}
+		toSave := rules
+		if modeTabs.Selected().Content == jsonView {
+			parsed, err := fromJSON()
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			toSave = parsed
+		}
+		if err := models.ValidateLifecycleRules(toSave); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		dialog.ShowConfirm("Salvar ciclo de vida",
+			fmt.Sprintf("Substituir as regras de %s por %d regra(s)?", bucket, len(toSave)),
+			func(confirm bool) {
+				if !confirm {
+					return
+				}
+				saveBtn.Disable()
+				go func() {
+					err := settings.PutLifecycleRules(context.Background(), bucket, toSave)
+					runOnUIThread(func() {
+						saveBtn.Enable()
+						if err != nil {
+							dialog.ShowError(err, w)
+							return
+						}
+						reload()
+					})
+				}()
+			}, w)
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	reloadBtn := widget.NewButton("Recarregar", reload)
+
+	reload()
+
+	return container.NewBorder(
+		nil,
+		container.NewBorder(nil, nil, status, container.NewHBox(reloadBtn, saveBtn)),
+		nil, nil,
+		modeTabs,
+	)
+}
+
+// parseDays converte o texto de um campo de dias (vazio = 0)
+func parseDays(text string) (int32, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(text, 10, 32)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("número de dias inválido: %q", text)
+	}
+	return int32(n), nil
+}
+
+func formatDays(days int32) string {
+	if days == 0 {
+		return ""
+	}
+	return strconv.Itoa(int(days))
+}
+
+// parseTransitions converte "30:STANDARD_IA, 90:GLACIER" em transições.
+// Classes desconhecidas ficam para a validação apontar.
+func parseTransitions(text string) ([]models.LifecycleTransition, error) {
+	var out []models.LifecycleTransition
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		days, class, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("transição inválida: %q (use dias:CLASSE)", part)
+		}
+		n, err := parseDays(days)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, models.LifecycleTransition{
+			Days:         n,
+			StorageClass: strings.ToUpper(strings.TrimSpace(class)),
+		})
+	}
+	return out, nil
+}
+
+func formatTransitions(transitions []models.LifecycleTransition) string {
+	parts := make([]string, 0, len(transitions))
+	for _, t := range transitions {
+		parts = append(parts, fmt.Sprintf("%d:%s", t.Days, t.StorageClass))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,153 @@
+// ui/bulk.go
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Requisições em paralelo nas operações em massa e no filtro por tags
+const bulkWorkers = 8
+
+// bulkAction é aplicada a cada chave de uma operação em massa
+type bulkAction func(ctx context.Context, key string) error
+
+// showBulkWindow abre uma janela com form e um botão "Aplicar" que aplica a
+// ação a keys ou, se keys for vazio, a todos os objetos sob prefix. prepare
+// valida o formulário (podendo pedir confirmações) e chama start com a ação.
+func showBulkWindow(w fyne.Window, client storageBackend, bucket, prefix string, keys []string,
+	title string, form fyne.CanvasObject, prepare func(bw fyne.Window, start func(bulkAction))) {
+
+	target := fmt.Sprintf("Todos os objetos em s3://%s/%s", bucket, prefix)
+	if len(keys) > 0 {
+		target = fmt.Sprintf("%d objeto(s) selecionado(s)", len(keys))
+	}
+
+	bw := fyne.CurrentApp().NewWindow(title)
+	bw.Resize(fyne.NewSize(620, 440))
+
+	status := widget.NewLabel("")
+	errorsEntry := widget.NewMultiLineEntry()
+	errorsEntry.Disable()
+
+	var cancel context.CancelFunc
+	var runBtn *widget.Button
+
+	start := func(action bulkAction) {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		runBtn.SetText("Parar")
+		errorsEntry.SetText("")
+
+		go func() {
+			var (
+				done, failed atomic.Int64
+				mu           sync.Mutex
+				failures     []string
+			)
+
+			jobs := make(chan string)
+			var wg sync.WaitGroup
+			for i := 0; i < bulkWorkers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for key := range jobs {
+						err := action(ctx, key)
+						if err != nil && ctx.Err() == nil {
+							failed.Add(1)
+							mu.Lock()
+							failures = append(failures, fmt.Sprintf("%s: %v", key, err))
+							mu.Unlock()
+						}
+						done.Add(1)
+					}
+				}()
+			}
+
+			// Atualizar o progresso sem sobrecarregar a interface
+			stopTicker := make(chan struct{})
+			go func() {
+				ticker := time.NewTicker(300 * time.Millisecond)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ticker.C:
+						runOnUIThread(func() {
+							status.SetText(fmt.Sprintf("%d objeto(s) processado(s), %d falha(s)...", done.Load(), failed.Load()))
+						})
+					case <-stopTicker:
+						return
+					}
+				}
+			}()
+
+			var walkErr error
+			if len(keys) > 0 {
+				for _, key := range keys {
+					select {
+					case jobs <- key:
+					case <-ctx.Done():
+					}
+				}
+			} else {
+				walkErr = client.WalkObjects(ctx, bucket, prefix, func(obj models.Object) error {
+					select {
+					case jobs <- obj.Key:
+						return nil
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				})
+			}
+			close(jobs)
+			wg.Wait()
+			close(stopTicker)
+
+			runOnUIThread(func() {
+				summary := fmt.Sprintf("%d objeto(s) processado(s), %d falha(s)", done.Load(), failed.Load())
+				switch {
+				case ctx.Err() != nil:
+					summary = "Interrompido: " + summary
+				case walkErr != nil:
+					summary = "Falha ao listar: " + walkErr.Error()
+				}
+				status.SetText(summary)
+				errorsEntry.SetText(strings.Join(failures, "\n"))
+				runBtn.SetText("Aplicar")
+				cancel = nil
+			})
+		}()
+	}
+
+	runBtn = widget.NewButton("Aplicar", func() {
+		if cancel != nil {
+			cancel()
+			return
+		}
+		prepare(bw, start)
+	})
+	runBtn.Importance = widget.HighImportance
+
+	bw.SetContent(container.NewBorder(
+		container.NewVBox(widget.NewLabel(target), form, container.NewHBox(runBtn), status),
+		nil, nil, nil,
+		errorsEntry,
+	))
+	bw.SetOnClosed(func() {
+		if cancel != nil {
+			cancel()
+		}
+	})
+	bw.Show()
+}
@@ -0,0 +1,195 @@
+// ui/conflict.go
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Acima deste número de envios os destinos são conferidos com uma única
+// listagem da pasta em vez de um HEAD por arquivo
+const conflictHeadLimit = 50
+
+// Maior sufixo tentado ao renomear (" (999)")
+const conflictMaxRename = 999
+
+// conflictPolicyFormItem é a escolha da política para chaves que já existem
+func conflictPolicyFormItem(initial models.ConflictPolicy) (*widget.FormItem, func() models.ConflictPolicy) {
+	labels := make([]string, len(models.ConflictPolicies))
+	for i, p := range models.ConflictPolicies {
+		labels[i] = p.Label()
+	}
+	policySelect := widget.NewSelect(labels, nil)
+	policySelect.SetSelected(initial.Label())
+
+	return widget.NewFormItem("Se já existir", policySelect), func() models.ConflictPolicy {
+		return models.ConflictPolicies[max(0, policySelect.SelectedIndex())]
+	}
+}
+
+// resolveConflicts confere quais destinos de jobs já existem e aplica
+// policy a cada um. Retorna os envios a fazer (com Overwrite quando a
+// política manda substituir) e quantos foram pulados. Com ConflictAsk,
+// pergunta arquivo a arquivo; deve rodar fora da thread da interface.
+func resolveConflicts(ctx context.Context, w fyne.Window, client storageBackend, bucket, prefix string, jobs []uploadJob, policy models.ConflictPolicy) ([]uploadJob, int, error) {
+	keys := make([]string, len(jobs))
+	for i, job := range jobs {
+		keys[i] = job.Key
+	}
+
+	// Poucos arquivos: HEAD em cada destino; muitos: listar a pasta uma vez
+	lookup, canLookup := client.(objectLookup)
+	listed := !canLookup || len(keys) > conflictHeadLimit
+	var (
+		existing map[string]models.Object
+		err      error
+	)
+	if listed {
+		existing = make(map[string]models.Object)
+		err = client.WalkObjects(ctx, bucket, prefix, func(obj models.Object) error {
+			existing[obj.Key] = obj
+			return nil
+		})
+	} else {
+		existing, err = lookup.StatObjects(ctx, bucket, keys)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Destinos já usados neste envio, para não renomear dois arquivos
+	// para a mesma chave
+	taken := make(map[string]bool, len(jobs))
+	exists := func(key string) (bool, error) {
+		if _, ok := existing[key]; ok || taken[key] {
+			return true, nil
+		}
+		if listed {
+			return false, nil
+		}
+		found, err := lookup.StatObjects(ctx, bucket, []string{key})
+		return len(found) > 0, err
+	}
+
+	var (
+		plan    []uploadJob
+		skipped int
+	)
+	for _, job := range jobs {
+		remote, found := existing[job.Key]
+		if !found {
+			taken[job.Key] = true
+			plan = append(plan, job)
+			continue
+		}
+
+		local, err := os.Stat(job.Path)
+		if err != nil {
+			return nil, 0, fmt.Errorf("falha ao ler %s: %w", job.Path, err)
+		}
+
+		choice := policy
+		if choice == models.ConflictAsk {
+			answer, applyAll := askConflict(w, bucket, job, local, remote)
+			if answer == "" {
+				return nil, 0, context.Canceled
+			}
+			choice = answer
+			if applyAll {
+				policy = answer
+			}
+		}
+
+		switch choice {
+		case models.ConflictOverwrite:
+			job.Overwrite = true
+		case models.ConflictNewer:
+			if !models.ReplacesRemote(local.Size(), local.ModTime(), remote) {
+				skipped++
+				continue
+			}
+			job.Overwrite = true
+		case models.ConflictRename:
+			key, err := freeKey(job.Key, exists)
+			if err != nil {
+				return nil, 0, err
+			}
+			job.Key = key
+		default:
+			skipped++
+			continue
+		}
+		taken[job.Key] = true
+		plan = append(plan, job)
+	}
+	return plan, skipped, nil
+}
+
+// freeKey procura o primeiro "nome (n).ext" livre para key
+func freeKey(key string, exists func(string) (bool, error)) (string, error) {
+	for n := 1; n <= conflictMaxRename; n++ {
+		candidate := models.NumberedKey(key, n)
+		used, err := exists(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !used {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("nenhum nome livre para %s", key)
+}
+
+// askConflict pergunta o que fazer com job, cujo destino já existe, e
+// espera a resposta. Retorna "" se o envio foi cancelado e, com applyAll,
+// que a resposta vale para os próximos conflitos.
+func askConflict(w fyne.Window, bucket string, job uploadJob, local os.FileInfo, remote models.Object) (models.ConflictPolicy, bool) {
+	answer := make(chan models.ConflictPolicy, 1)
+	applyAll := widget.NewCheck("Fazer o mesmo para os próximos conflitos", nil)
+
+	runOnUIThread(func() {
+		remoteText := "tamanho e data desconhecidos"
+		if !remote.LastModified.IsZero() {
+			remoteText = fmt.Sprintf("%s, modificado em %s",
+				formatBytes(remote.Size), remote.LastModified.Local().Format("2006-01-02 15:04"))
+		}
+		message := widget.NewLabel(fmt.Sprintf("s3://%s/%s já existe.\n\nNo bucket: %s\nLocal: %s, modificado em %s",
+			bucket, job.Key, remoteText,
+			formatBytes(local.Size()), local.ModTime().Format("2006-01-02 15:04")))
+
+		var d dialog.Dialog
+		choose := func(p models.ConflictPolicy) func() {
+			return func() {
+				d.Hide()
+				answer <- p
+			}
+		}
+		overwriteBtn := widget.NewButton("Sobrescrever", choose(models.ConflictOverwrite))
+		overwriteBtn.Importance = widget.DangerImportance
+		renameBtn := widget.NewButton("Renomear", choose(models.ConflictRename))
+		renameBtn.Importance = widget.HighImportance
+
+		d = dialog.NewCustomWithoutButtons("Arquivo já existe", container.NewVBox(
+			message,
+			applyAll,
+			container.NewHBox(
+				widget.NewButton("Cancelar envio", choose("")),
+				widget.NewButton("Pular", choose(models.ConflictSkip)),
+				renameBtn,
+				overwriteBtn,
+			),
+		), w)
+		d.Show()
+	})
+
+	p := <-answer
+	return p, applyAll.Checked
+}
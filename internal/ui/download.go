@@ -0,0 +1,77 @@
+// ui/download.go
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"s3nd-files/internal/models"
+	"s3nd-files/internal/services/aws"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// showDownloadDialog pergunta onde salvar o objeto e baixa em segundo plano
+func showDownloadDialog(w fyne.Window, client storageBackend, bucket string, item models.Item) {
+	saveDialog := dialog.NewFileSave(func(wc fyne.URIWriteCloser, err error) {
+		if err != nil || wc == nil {
+			return
+		}
+		dest := wc.URI().Path()
+		// O backend grava o arquivo por conta própria
+		wc.Close()
+
+		var start func()
+		start = func() {
+			progress := dialog.NewProgressInfinite("Baixando",
+				fmt.Sprintf("Baixando %s/%s...", bucket, item.Prefix), w)
+			progress.Show()
+
+			go func() {
+				err := client.DownloadFile(context.Background(), bucket, item.Prefix, dest)
+				runOnUIThread(func() {
+					progress.Hide()
+					if errors.Is(err, aws.ErrArchived) {
+						showRestoreDialog(w, client, bucket, item)
+						return
+					}
+					if err != nil {
+						if !handleKeyError(w, client, err, start) {
+							dialog.ShowError(err, w)
+						}
+						return
+					}
+					dialog.ShowInformation("Download concluído",
+						fmt.Sprintf("Arquivo salvo em:\n%s", dest), w)
+				})
+			}()
+		}
+		start()
+	}, w)
+	saveDialog.SetFileName(item.Name)
+	saveDialog.Show()
+}
+
+// formatObjectInfo descreve os metadados de um objeto para o diálogo de informações
+func formatObjectInfo(info models.ObjectInfo) string {
+	encryption := info.Encryption
+	switch {
+	case encryption == models.SSENone:
+		encryption = "nenhuma"
+	case info.KMSKeyID != "":
+		encryption += " (" + info.KMSKeyID + ")"
+	}
+
+	if info.ClientEncrypted {
+		encryption += ", cifrado no cliente"
+	}
+
+	return fmt.Sprintf("Tamanho: %s\nModificado: %s\nTipo: %s\nClasse: %s\nCriptografia: %s",
+		formatBytes(info.Size),
+		info.LastModified.Local().Format("2006-01-02 15:04:05"),
+		info.ContentType,
+		info.StorageClass,
+		encryption)
+}
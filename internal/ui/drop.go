@@ -0,0 +1,83 @@
+// ui/drop.go
+package ui
+
+import (
+	"path/filepath"
+	"time"
+
+	"s3nd-files/internal/filter"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// uploadJob é um arquivo local e a chave de destino
+type uploadJob struct {
+	Path string
+	// Rel é o caminho relativo usado pelo modelo de chave (ex: fotos/a.jpg)
+	Rel string
+	Key string
+	// Overwrite permite substituir um objeto existente; sem ele o envio só
+	// grava se a chave estiver livre
+	Overwrite bool
+}
+
+// droppedPaths expande arquivos e pastas soltos na janela nos arquivos que
+// passam pelos filtros
+func droppedPaths(uris []fyne.URI, rules filter.Rules) []string {
+	var paths []string
+	now := time.Now()
+	for _, u := range uris {
+		if u.Scheme() != "file" {
+			continue
+		}
+		filter.Walk(u.Path(), rules, now, func(e filter.Entry) {
+			if !e.Excluded() {
+				paths = append(paths, e.Path)
+			}
+		})
+	}
+	return paths
+}
+
+// dropJobs monta os envios dos itens soltos. Pastas soltas mantêm a
+// estrutura a partir do próprio nome (ex: fotos/2024/a.jpg).
+func dropJobs(uris []fyne.URI, rules filter.Rules) []uploadJob {
+	var jobs []uploadJob
+	for _, u := range uris {
+		if u.Scheme() != "file" {
+			continue
+		}
+		parent := filepath.Dir(u.Path())
+		for _, path := range droppedPaths([]fyne.URI{u}, rules) {
+			rel, err := filepath.Rel(parent, path)
+			if err != nil {
+				rel = filepath.Base(path)
+			}
+			jobs = append(jobs, uploadJob{Path: path, Rel: filepath.ToSlash(rel)})
+		}
+	}
+	return jobs
+}
+
+// dropPosition converte pos (coordenadas da janela) para a posição relativa
+// a obj e informa se ela cai sobre obj
+func dropPosition(obj fyne.CanvasObject, pos fyne.Position) (fyne.Position, bool) {
+	if !obj.Visible() {
+		return pos, false
+	}
+	rel := pos.Subtract(fyne.CurrentApp().Driver().AbsolutePositionForObject(obj))
+	size := obj.Size()
+	return rel, rel.X >= 0 && rel.Y >= 0 && rel.X < size.Width && rel.Y < size.Height
+}
+
+// listRowAt retorna a linha da lista (de rótulos) sob rel, ou -1
+func listRowAt(list *widget.List, rel fyne.Position, length int) int {
+	rowHeight := widget.NewLabel("").MinSize().Height + theme.Padding()
+	row := int((rel.Y + list.GetScrollOffset()) / rowHeight)
+	if row < 0 || row >= length {
+		return -1
+	}
+	return row
+}
@@ -0,0 +1,246 @@
+// ui/editor.go
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"s3nd-files/internal/diff"
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Maior objeto aberto no editor de texto
+const editorMaxSize = 5 << 20
+
+// readTextObject lê bucket/key inteiro como texto editável
+func readTextObject(ctx context.Context, reader objectReader, bucket, key string) (string, models.ObjectInfo, error) {
+	rc, info, err := reader.OpenObject(ctx, bucket, key, editorMaxSize)
+	if err != nil {
+		return "", info, err
+	}
+	defer rc.Close()
+
+	switch {
+	case info.Size > editorMaxSize:
+		return "", info, fmt.Errorf("arquivo grande demais para editar (%s, limite %s)",
+			formatBytes(info.Size), formatBytes(editorMaxSize))
+	case info.ClientEncrypted:
+		return "", info, fmt.Errorf("objetos cifrados no cliente não podem ser editados")
+	case info.ContentEncoding == "gzip":
+		return "", info, fmt.Errorf("objetos comprimidos não podem ser editados")
+	}
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return "", info, fmt.Errorf("falha ao ler objeto: %w", err)
+	}
+	if !looksLikeText(data) {
+		return "", info, fmt.Errorf("o conteúdo não parece ser texto")
+	}
+	return string(data), info, nil
+}
+
+// showTextEditor abre bucket/key para edição e o regrava só se ninguém o
+// alterou desde a leitura; em caso de conflito, mostra as diferenças
+func showTextEditor(w fyne.Window, client storageBackend, bucket, key string) {
+	reader, canRead := client.(objectReader)
+	saver, canSave := client.(objectSaver)
+	if !canRead || !canSave {
+		dialog.ShowInformation("Editar", "Este backend não suporta edição de objetos", w)
+		return
+	}
+
+	ew := fyne.CurrentApp().NewWindow("Editando " + key)
+	ew.Resize(fyne.NewSize(900, 650))
+
+	var (
+		base     models.ObjectInfo
+		original string
+		loaded   bool
+	)
+
+	editor := widget.NewMultiLineEntry()
+	editor.TextStyle = fyne.TextStyle{Monospace: true}
+	editor.Wrapping = fyne.TextWrapOff
+	editor.Disable()
+
+	status := widget.NewLabel("Carregando...")
+	title := fmt.Sprintf("%s/%s", bucket, key)
+	editor.OnChanged = func(text string) {
+		if loaded && text != original {
+			ew.SetTitle("* Editando " + key)
+		} else {
+			ew.SetTitle("Editando " + key)
+		}
+	}
+
+	// setBase troca a versão de referência e o texto do editor
+	setBase := func(text string, info models.ObjectInfo) {
+		base, original, loaded = info, text, true
+		editor.SetText(text)
+		editor.Enable()
+	}
+
+	var reload func()
+	reload = func() {
+		editor.Disable()
+		status.SetText("Carregando...")
+		go func() {
+			text, info, err := readTextObject(context.Background(), reader, bucket, key)
+			runOnUIThread(func() {
+				if err != nil {
+					status.SetText("Falha ao carregar")
+					if !handleKeyError(ew, client, err, reload) {
+						dialog.ShowError(err, ew)
+					}
+					return
+				}
+				setBase(text, info)
+				status.SetText(fmt.Sprintf("%s · %s · modificado em %s", title,
+					formatBytes(info.Size), info.LastModified.Local().Format("2006-01-02 15:04")))
+			})
+		}()
+	}
+
+	var save func(text string, against models.ObjectInfo)
+	var showConflict func(text string)
+	var saveBtn *widget.Button
+
+	// O botão fica desativado enquanto um envio está em andamento
+	save = func(text string, against models.ObjectInfo) {
+		status.SetText("Salvando...")
+		saveBtn.Disable()
+		go func() {
+			saved, err := saver.SaveObject(context.Background(), bucket, key, []byte(text), against)
+			runOnUIThread(func() {
+				saveBtn.Enable()
+				switch {
+				case errors.Is(err, models.ErrConflict):
+					status.SetText("Conflito: o objeto foi alterado")
+					showConflict(text)
+				case err != nil:
+					status.SetText("Falha ao salvar")
+					dialog.ShowError(err, ew)
+				default:
+					base, original = saved, text
+					editor.OnChanged(editor.Text)
+					status.SetText(fmt.Sprintf("%s · salvo às %s", title, time.Now().Format("15:04:05")))
+				}
+			})
+		}()
+	}
+
+	showConflict = func(mine string) {
+		go func() {
+			remote, info, err := readTextObject(context.Background(), reader, bucket, key)
+			runOnUIThread(func() {
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("%w; falha ao ler a versão atual: %v", models.ErrConflict, err), ew)
+					return
+				}
+
+				lines := diff.Lines(remote, mine)
+				diffLabel := widget.NewLabel(diff.Format(lines, 3))
+				diffLabel.TextStyle = fyne.TextStyle{Monospace: true}
+				scroll := container.NewScroll(diffLabel)
+				scroll.SetMinSize(fyne.NewSize(640, 360))
+
+				var d dialog.Dialog
+				overwriteBtn := widget.NewButton("Sobrescrever", func() {
+					d.Hide()
+					save(mine, info)
+				})
+				overwriteBtn.Importance = widget.DangerImportance
+				mergeBtn := widget.NewButton("Mesclar no editor", func() {
+					d.Hide()
+					setBase(remote, info)
+					editor.SetText(diff.Conflicts(lines, "remoto", "local"))
+					status.SetText("Resolva os trechos marcados e salve")
+				})
+				mergeBtn.Importance = widget.HighImportance
+				discardBtn := widget.NewButton("Usar a versão remota", func() {
+					d.Hide()
+					setBase(remote, info)
+					status.SetText("Versão remota carregada")
+				})
+
+				content := container.NewBorder(
+					widget.NewLabel(fmt.Sprintf(
+						"%s foi alterado em %s, depois de ser aberto.\nDiferenças da versão remota (-) para a sua (+):",
+						key, info.LastModified.Local().Format("2006-01-02 15:04"))),
+					container.NewHBox(widget.NewButton("Cancelar", func() { d.Hide() }),
+						discardBtn, mergeBtn, overwriteBtn),
+					nil, nil,
+					scroll,
+				)
+				d = dialog.NewCustomWithoutButtons("Conflito ao salvar", content, ew)
+				d.Show()
+			})
+		}()
+	}
+
+	saveBtn = widget.NewButton("💾 Salvar", func() {
+		if !loaded {
+			return
+		}
+		text := editor.Text
+		if text == original {
+			status.SetText("Nenhuma alteração")
+			return
+		}
+		if strings.Contains(text, "<<<<<<< remoto") || strings.Contains(text, ">>>>>>> local") {
+			dialog.ShowConfirm("Conflitos não resolvidos",
+				"O texto ainda tem marcadores de conflito. Salvar mesmo assim?",
+				func(ok bool) {
+					if ok {
+						save(text, base)
+					}
+				}, ew)
+			return
+		}
+		save(text, base)
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	reloadBtn := widget.NewButton("Recarregar", func() {
+		if editor.Text == original {
+			reload()
+			return
+		}
+		dialog.ShowConfirm("Recarregar", "Descartar as alterações e recarregar?", func(ok bool) {
+			if ok {
+				reload()
+			}
+		}, ew)
+	})
+
+	ew.SetCloseIntercept(func() {
+		if !loaded || editor.Text == original {
+			ew.Close()
+			return
+		}
+		dialog.ShowConfirm("Alterações não salvas", "Fechar e descartar as alterações?", func(ok bool) {
+			if ok {
+				ew.Close()
+			}
+		}, ew)
+	})
+
+	ew.SetContent(container.NewBorder(
+		nil,
+		container.NewBorder(nil, nil, status, container.NewHBox(reloadBtn, saveBtn)),
+		nil, nil,
+		editor,
+	))
+	reload()
+	ew.Show()
+}
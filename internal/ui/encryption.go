@@ -0,0 +1,215 @@
+// ui/encryption.go
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"s3nd-files/internal/envelope"
+	"s3nd-files/internal/models"
+	"s3nd-files/internal/services/aws"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Rótulo exibido para models.SSENone
+const sseBucketDefault = "Padrão do bucket"
+
+// encryptionFormItems monta os campos de criptografia usados na conexão e
+// no upload. O retorno lê e valida o que foi preenchido; sem arquivo de
+// chave, SSE-C reaproveita a chave de initial.
+func encryptionFormItems(w fyne.Window, initial models.Encryption) ([]*widget.FormItem, func() (models.Encryption, error)) {
+	kmsEntry := widget.NewEntry()
+	kmsEntry.SetPlaceHolder("vazio = chave aws/s3")
+	kmsEntry.SetText(initial.KMSKeyID)
+
+	keyFileEntry := widget.NewEntry()
+	keyFileEntry.SetPlaceHolder("arquivo com 32 bytes (binário, base64 ou hex)")
+	if initial.CustomerKey != nil {
+		keyFileEntry.SetPlaceHolder("usar a chave já carregada")
+	}
+	browseBtn := widget.NewButton("...", func() {
+		dialog.ShowFileOpen(func(rc fyne.URIReadCloser, err error) {
+			if err != nil || rc == nil {
+				return
+			}
+			keyFileEntry.SetText(rc.URI().Path())
+			rc.Close()
+		}, w)
+	})
+
+	options := []string{sseBucketDefault}
+	options = append(options, models.EncryptionModes[1:]...)
+	modeSelect := widget.NewSelect(options, func(mode string) {
+		if mode == models.SSEKMS {
+			kmsEntry.Enable()
+		} else {
+			kmsEntry.Disable()
+		}
+		if mode == models.SSEC {
+			keyFileEntry.Enable()
+			browseBtn.Enable()
+		} else {
+			keyFileEntry.Disable()
+			browseBtn.Disable()
+		}
+	})
+	if initial.Mode == models.SSENone {
+		modeSelect.SetSelected(sseBucketDefault)
+	} else {
+		modeSelect.SetSelected(initial.Mode)
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Criptografia", modeSelect),
+		widget.NewFormItem("Chave KMS", kmsEntry),
+		widget.NewFormItem("Chave SSE-C", container.NewBorder(nil, nil, nil, browseBtn, keyFileEntry)),
+	}
+
+	read := func() (models.Encryption, error) {
+		enc := models.Encryption{Mode: modeSelect.Selected}
+		if enc.Mode == sseBucketDefault {
+			enc.Mode = models.SSENone
+		}
+
+		switch enc.Mode {
+		case models.SSEKMS:
+			enc.KMSKeyID = strings.TrimSpace(kmsEntry.Text)
+		case models.SSEC:
+			enc.CustomerKey = initial.CustomerKey
+			if path := strings.TrimSpace(keyFileEntry.Text); path != "" {
+				key, err := models.LoadKeyFile(path)
+				if err != nil {
+					return models.Encryption{}, err
+				}
+				enc.CustomerKey = key
+			}
+		}
+
+		return enc, enc.Validate()
+	}
+
+	return items, read
+}
+
+// masterKeyFormItems monta os campos da chave mestra da criptografia no
+// cliente. Com confirm, a senha é pedida duas vezes (chave nova, um erro
+// de digitação deixaria os objetos ilegíveis). O retorno devolve nil
+// quando nada foi preenchido.
+func masterKeyFormItems(w fyne.Window, confirm bool) ([]*widget.FormItem, func() (*envelope.MasterKey, error)) {
+	passphraseEntry := widget.NewPasswordEntry()
+	passphraseEntry.SetPlaceHolder("mínimo 8 caracteres")
+	confirmEntry := widget.NewPasswordEntry()
+	confirmEntry.SetPlaceHolder("repita a senha")
+
+	keyFileEntry := widget.NewEntry()
+	keyFileEntry.SetPlaceHolder("ou arquivo com 32 bytes (binário, base64 ou hex)")
+	browseBtn := widget.NewButton("...", func() {
+		dialog.ShowFileOpen(func(rc fyne.URIReadCloser, err error) {
+			if err != nil || rc == nil {
+				return
+			}
+			keyFileEntry.SetText(rc.URI().Path())
+			rc.Close()
+		}, w)
+	})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Senha (cliente)", passphraseEntry),
+	}
+	if confirm {
+		items = append(items, widget.NewFormItem("Confirmar senha", confirmEntry))
+	}
+	items = append(items, widget.NewFormItem("Chave (cliente)", container.NewBorder(nil, nil, nil, browseBtn, keyFileEntry)))
+
+	read := func() (*envelope.MasterKey, error) {
+		path := strings.TrimSpace(keyFileEntry.Text)
+		switch {
+		case passphraseEntry.Text != "" && path != "":
+			return nil, fmt.Errorf("informe a senha ou o arquivo de chave, não os dois")
+		case passphraseEntry.Text != "":
+			if confirm && confirmEntry.Text != passphraseEntry.Text {
+				return nil, fmt.Errorf("a confirmação não confere com a senha")
+			}
+			return envelope.FromPassphrase(passphraseEntry.Text)
+		case path != "":
+			return envelope.FromKeyFile(path)
+		}
+		return nil, nil
+	}
+
+	return items, read
+}
+
+// askMasterKey pede a chave mestra do cliente e chama then depois de carregada
+func askMasterKey(w fyne.Window, ce clientEncrypter, then func()) {
+	items, read := masterKeyFormItems(w, false)
+	dialog.ShowForm("Chave do cliente", "Carregar", "Cancelar", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		m, err := read()
+		if err == nil && m == nil {
+			err = fmt.Errorf("informe a senha ou o arquivo de chave")
+		}
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		ce.SetMasterKey(m)
+		then()
+	}, w)
+}
+
+// handleKeyError oferece carregar a chave que falta (SSE-C ou chave mestra
+// do cliente) quando err indica isso, e chama retry depois de carregada.
+// Retorna false se err não for desse tipo.
+func handleKeyError(w fyne.Window, client storageBackend, err error, retry func()) bool {
+	if ce, ok := client.(clientEncrypter); ok &&
+		(errors.Is(err, aws.ErrClientKeyRequired) || errors.Is(err, envelope.ErrWrongKey)) {
+		dialog.ShowConfirm("Objeto cifrado no cliente",
+			err.Error()+"\n\nInformar a chave e tentar novamente?",
+			func(load bool) {
+				if load {
+					askMasterKey(w, ce, retry)
+				}
+			}, w)
+		return true
+	}
+
+	cfg, ok := client.(sseConfigurer)
+	if !ok || !errors.Is(err, aws.ErrCustomerKeyRequired) {
+		return false
+	}
+
+	dialog.ShowConfirm("Chave SSE-C necessária",
+		"Este objeto está criptografado com uma chave do cliente (SSE-C).\n\n"+
+			"Carregar a chave de um arquivo e tentar novamente?",
+		func(load bool) {
+			if !load {
+				return
+			}
+			dialog.ShowFileOpen(func(rc fyne.URIReadCloser, err error) {
+				if err != nil || rc == nil {
+					return
+				}
+				path := rc.URI().Path()
+				rc.Close()
+
+				key, err := models.LoadKeyFile(path)
+				if err == nil {
+					err = cfg.SetCustomerKey(key)
+				}
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				retry()
+			}, w)
+		}, w)
+	return true
+}
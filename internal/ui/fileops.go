@@ -0,0 +1,258 @@
+// ui/fileops.go
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// fileClipboard guarda o que foi copiado com Ctrl+C: itens de uma pasta
+// do bucket ou caminhos de arquivos locais
+type fileClipboard struct {
+	client storageBackend
+	bucket string
+	prefix string
+	items  []models.Item
+	paths  []string
+}
+
+// walkItems percorre os objetos de items, descendo nas pastas, e informa
+// a chave de cada um e o caminho relativo a prefix
+func walkItems(ctx context.Context, client storageBackend, bucket, prefix string, items []models.Item, fn func(key, rel string) error) error {
+	for _, item := range items {
+		switch item.Type {
+		case models.File:
+			if err := fn(item.Prefix, strings.TrimPrefix(item.Prefix, prefix)); err != nil {
+				return err
+			}
+		case models.Folder:
+			err := client.WalkObjects(ctx, bucket, item.Prefix, func(obj models.Object) error {
+				return fn(obj.Key, strings.TrimPrefix(obj.Key, prefix))
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkCopyTarget recusa colar os itens na própria pasta ou uma pasta
+// dentro dela mesma
+func checkCopyTarget(clip fileClipboard, bucket, prefix string) error {
+	if clip.bucket != bucket {
+		return nil
+	}
+	if clip.prefix == prefix {
+		return fmt.Errorf("os itens copiados já estão nesta pasta")
+	}
+	for _, item := range clip.items {
+		if item.Type == models.Folder && strings.HasPrefix(prefix, item.Prefix) {
+			return fmt.Errorf("não é possível copiar %s para dentro dela mesma", item.Name)
+		}
+	}
+	return nil
+}
+
+// copyItems copia no servidor os itens de clip para bucket/prefix,
+// perguntando antes de sobrescrever destinos que já existem
+func copyItems(ctx context.Context, w fyne.Window, copier objectCopier, clip fileClipboard, bucket, prefix string, step func(string)) error {
+	step("Listando...")
+	var srcs, dests []string
+	err := walkItems(ctx, clip.client, clip.bucket, clip.prefix, clip.items, func(key, rel string) error {
+		srcs = append(srcs, key)
+		dests = append(dests, prefix+rel)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := askOverwrite(ctx, w, clip.client, bucket, prefix, dests); err != nil {
+		return err
+	}
+
+	for i, key := range srcs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		step(fmt.Sprintf("Copiando %s (%d de %d)...", strings.TrimPrefix(dests[i], prefix), i+1, len(srcs)))
+		if err := copier.CopyObject(ctx, clip.bucket, key, bucket, dests[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// askOverwrite confere quais de dests (todos sob prefix) já existem e, se
+// houver algum, pergunta se devem ser sobrescritos. Deve rodar fora da
+// thread da interface; retorna context.Canceled se o usuário desistir.
+func askOverwrite(ctx context.Context, w fyne.Window, client storageBackend, bucket, prefix string, dests []string) error {
+	// Como em resolveConflicts: HEAD em poucos destinos, listagem em muitos
+	var existing []string
+	if lookup, ok := client.(objectLookup); ok && len(dests) <= conflictHeadLimit {
+		found, err := lookup.StatObjects(ctx, bucket, dests)
+		if err != nil {
+			return err
+		}
+		for _, key := range dests {
+			if _, ok := found[key]; ok {
+				existing = append(existing, key)
+			}
+		}
+	} else {
+		wanted := make(map[string]bool, len(dests))
+		for _, key := range dests {
+			wanted[key] = true
+		}
+		err := client.WalkObjects(ctx, bucket, prefix, func(obj models.Object) error {
+			if wanted[obj.Key] {
+				existing = append(existing, obj.Key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+
+	names := existing
+	more := ""
+	if len(names) > 10 {
+		names, more = names[:10], fmt.Sprintf("\n... e mais %d", len(existing)-10)
+	}
+	answer := make(chan bool, 1)
+	runOnUIThread(func() {
+		dialog.ShowConfirm("Sobrescrever?",
+			fmt.Sprintf("%d destino(s) já existem em s3://%s:\n\n%s%s\n\nSobrescrever?",
+				len(existing), bucket, strings.Join(names, "\n"), more),
+			func(ok bool) { answer <- ok }, w)
+	})
+	if !<-answer {
+		return context.Canceled
+	}
+	return nil
+}
+
+// deleteItems exclui os objetos de items, inclusive o conteúdo das pastas
+func deleteItems(ctx context.Context, client storageBackend, bucket, prefix string, items []models.Item, step func(string)) error {
+	// Listar antes de excluir para não alterar a paginação em andamento
+	var keys []string
+	err := walkItems(ctx, client, bucket, prefix, items, func(key, _ string) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		step(fmt.Sprintf("Excluindo %d de %d...", i+1, len(keys)))
+		if err := client.DeleteObject(ctx, bucket, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameItem move item para newName na mesma pasta (copiar e excluir),
+// perguntando antes de sobrescrever destinos que já existem
+func renameItem(ctx context.Context, w fyne.Window, client storageBackend, copier objectCopier, bucket, prefix string, item models.Item, newName string, step func(string)) error {
+	oldName := strings.TrimSuffix(item.Name, "/")
+	step("Listando...")
+	var srcs, dests []string
+	err := walkItems(ctx, client, bucket, prefix, []models.Item{item}, func(key, rel string) error {
+		srcs = append(srcs, key)
+		dests = append(dests, prefix+newName+strings.TrimPrefix(rel, oldName))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := askOverwrite(ctx, w, client, bucket, prefix+newName, dests); err != nil {
+		return err
+	}
+
+	var moved []string
+	for i, key := range srcs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		step(fmt.Sprintf("Copiando %s...", strings.TrimPrefix(key, prefix)))
+		if err := copier.CopyObject(ctx, bucket, key, bucket, dests[i]); err != nil {
+			return err
+		}
+		moved = append(moved, key)
+	}
+
+	for _, key := range moved {
+		step(fmt.Sprintf("Removendo %s...", strings.TrimPrefix(key, prefix)))
+		if err := client.DeleteObject(ctx, bucket, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// downloadItems baixa os itens de clip para dir, recriando as subpastas
+func downloadItems(ctx context.Context, clip fileClipboard, dir string, step func(string)) error {
+	count := 0
+	return walkItems(ctx, clip.client, clip.bucket, clip.prefix, clip.items, func(key, rel string) error {
+		if strings.HasSuffix(key, "/") {
+			return nil
+		}
+		count++
+		step(fmt.Sprintf("Baixando %s (%d)...", rel, count))
+
+		dest := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return fmt.Errorf("falha ao criar diretório: %w", err)
+		}
+		return clip.client.DownloadFile(ctx, clip.bucket, key, dest)
+	})
+}
+
+// runFileOperation executa work em segundo plano com um diálogo de
+// progresso que pode ser cancelado. onDone recebe o resultado (erros já
+// foram mostrados) e pode ser nil.
+func runFileOperation(w fyne.Window, title string, work func(ctx context.Context, step func(string)) error, onDone func(err error)) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	status := widget.NewLabel("Iniciando...")
+	bar := widget.NewProgressBarInfinite()
+	d := dialog.NewCustomWithoutButtons(title, container.NewVBox(status, bar,
+		widget.NewButton("Cancelar", cancel)), w)
+	d.Show()
+
+	go func() {
+		err := work(ctx, func(text string) {
+			runOnUIThread(func() { status.SetText(text) })
+		})
+		cancel()
+		runOnUIThread(func() {
+			d.Hide()
+			if err != nil && !errors.Is(err, context.Canceled) {
+				dialog.ShowError(err, w)
+			}
+			if onDone != nil {
+				onDone(err)
+			}
+		})
+	}()
+}
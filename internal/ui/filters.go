@@ -0,0 +1,123 @@
+// ui/filters.go
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"s3nd-files/internal/filter"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showFolderFilterDialog edita os filtros aplicados ao adicionar pastas
+// locais e chama onSave com as regras novas
+func showFolderFilterDialog(w fyne.Window, rules filter.Rules, onSave func(filter.Rules)) {
+	includeEntry := widget.NewMultiLineEntry()
+	includeEntry.SetPlaceHolder("*.jpg\nrelatorios/**")
+	includeEntry.SetMinRowsVisible(3)
+	excludeEntry := widget.NewMultiLineEntry()
+	excludeEntry.SetMinRowsVisible(6)
+	gitCheck := widget.NewCheck("Respeitar "+filter.GitIgnoreFile, nil)
+	s3ndCheck := widget.NewCheck("Respeitar "+filter.S3ndIgnoreFile, nil)
+	minSizeEntry := widget.NewEntry()
+	maxSizeEntry := widget.NewEntry()
+	maxAgeEntry := widget.NewEntry()
+	minAgeEntry := widget.NewEntry()
+	for _, e := range []*widget.Entry{minSizeEntry, maxSizeEntry, maxAgeEntry, minAgeEntry} {
+		e.SetPlaceHolder("sem limite")
+	}
+
+	show := func(r filter.Rules) {
+		includeEntry.SetText(strings.Join(r.Include, "\n"))
+		excludeEntry.SetText(strings.Join(r.Exclude, "\n"))
+		gitCheck.SetChecked(r.GitIgnore)
+		s3ndCheck.SetChecked(r.S3ndIgnore)
+		minSizeEntry.SetText(formatLimit(r.MinSize >> 20))
+		maxSizeEntry.SetText(formatLimit(r.MaxSize >> 20))
+		maxAgeEntry.SetText(formatLimit(int64(r.MaxAgeDays)))
+		minAgeEntry.SetText(formatLimit(int64(r.MinAgeDays)))
+	}
+	show(rules)
+
+	form := widget.NewForm(
+		widget.NewFormItem("Incluir somente", includeEntry),
+		widget.NewFormItem("Excluir", excludeEntry),
+		widget.NewFormItem("", gitCheck),
+		widget.NewFormItem("", s3ndCheck),
+		widget.NewFormItem("Tamanho mínimo (MB)", minSizeEntry),
+		widget.NewFormItem("Tamanho máximo (MB)", maxSizeEntry),
+		widget.NewFormItem("Modificados nos últimos (dias)", maxAgeEntry),
+		widget.NewFormItem("Modificados há pelo menos (dias)", minAgeEntry),
+	)
+	help := widget.NewLabel("Um padrão por linha, como no .gitignore: \"*.log\", \"build/\" (só pastas),\n" +
+		"\"/raiz\" (só na pasta escolhida), \"docs/**/*.md\" e \"!mantido.log\" (reincluir).")
+	help.Importance = widget.LowImportance
+
+	content := container.NewBorder(nil,
+		container.NewVBox(help, widget.NewButton("Restaurar padrões", func() { show(filter.DefaultRules()) })),
+		nil, nil, container.NewVScroll(form))
+
+	d := dialog.NewCustomConfirm("Filtros de pastas", "Aplicar", "Cancelar", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		limits := make([]int64, 4)
+		for i, e := range []*widget.Entry{minSizeEntry, maxSizeEntry, maxAgeEntry, minAgeEntry} {
+			n, err := parseLimit(e.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			limits[i] = n
+		}
+		onSave(filter.Rules{
+			Include:    splitLines(includeEntry.Text),
+			Exclude:    splitLines(excludeEntry.Text),
+			GitIgnore:  gitCheck.Checked,
+			S3ndIgnore: s3ndCheck.Checked,
+			MinSize:    limits[0] << 20,
+			MaxSize:    limits[1] << 20,
+			MaxAgeDays: int(limits[2]),
+			MinAgeDays: int(limits[3]),
+		})
+	}, w)
+	d.Resize(fyne.NewSize(560, 600))
+	d.Show()
+}
+
+// formatLimit mostra 0 como campo vazio (sem limite)
+func formatLimit(n int64) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// parseLimit lê um limite inteiro; vazio é 0 (sem limite)
+func parseLimit(text string) (int64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(text, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("limite inválido: %q", text)
+	}
+	return n, nil
+}
+
+// splitLines separa as linhas não vazias de text
+func splitLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,117 @@
+// ui/keytemplate.go
+package ui
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"s3nd-files/internal/naming"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Arquivos mostrados na prévia das chaves
+const keyPreviewLimit = 8
+
+// keyTemplateSection é a escolha do modelo de chave, entre os salvos ou
+// digitado, com a prévia das chaves dos primeiros jobs em prefix
+func keyTemplateSection(w fyne.Window, saved []string, jobs []uploadJob, prefix string) (fyne.CanvasObject, func() (*naming.Template, error)) {
+	// Manter as pastas fica a um clique, mesmo sem histórico
+	options := saved
+	if !slices.Contains(saved, naming.TreeTemplate) {
+		options = append(slices.Clip(saved), naming.TreeTemplate)
+	}
+	templateEntry := widget.NewSelectEntry(options)
+	templateEntry.SetPlaceHolder(naming.DefaultTemplate)
+	if len(saved) > 0 {
+		templateEntry.SetText(saved[0])
+	}
+	helpBtn := widget.NewButton("?", func() {
+		dialog.ShowInformation("Marcadores do modelo", naming.Help+
+			"\n\nExemplo: raw/{exif.yyyy}/{exif.mm}/{exif.dd}/{name}", w)
+	})
+
+	preview := widget.NewLabel("")
+	preview.TextStyle = fyne.TextStyle{Monospace: true}
+	preview.Truncation = fyne.TextTruncateEllipsis
+
+	read := func() (*naming.Template, error) {
+		text := strings.TrimSpace(templateEntry.Text)
+		if text == "" {
+			text = naming.DefaultTemplate
+		}
+		return naming.Parse(text)
+	}
+
+	// A prévia pode ler os arquivos ({hash}, EXIF): roda em segundo plano
+	// e só a mais recente é mostrada
+	generation := 0
+	update := func(string) {
+		generation++
+		current := generation
+		tmpl, err := read()
+		if err != nil {
+			preview.SetText("⚠️ " + err.Error())
+			return
+		}
+		sample := jobs[:min(len(jobs), keyPreviewLimit)]
+		go func() {
+			var lines []string
+			planned, err := applyKeyTemplate(sample, prefix, tmpl, naming.NewEnv())
+			if err != nil {
+				lines = []string{"⚠️ " + err.Error()}
+			}
+			for _, job := range planned {
+				lines = append(lines, job.Key)
+			}
+			if more := len(jobs) - len(sample); more > 0 && err == nil {
+				lines = append(lines, fmt.Sprintf("... e mais %d arquivo(s)", more))
+			}
+			runOnUIThread(func() {
+				if current == generation {
+					preview.SetText(strings.Join(lines, "\n"))
+				}
+			})
+		}()
+	}
+	templateEntry.OnChanged = update
+	update(templateEntry.Text)
+
+	content := container.NewVBox(
+		widget.NewForm(widget.NewFormItem("Modelo da chave",
+			container.NewBorder(nil, nil, nil, helpBtn, templateEntry))),
+		widget.NewLabel("Prévia:"),
+		preview,
+	)
+	return content, read
+}
+
+// applyKeyTemplate define a chave de cada job em prefix com tmpl. Lê os
+// arquivos quando o modelo usa {hash} ou EXIF, então deve rodar fora da
+// thread da interface quando houver muitos.
+func applyKeyTemplate(jobs []uploadJob, prefix string, tmpl *naming.Template, env naming.Env) ([]uploadJob, error) {
+	planned := make([]uploadJob, 0, len(jobs))
+	sources := make(map[string]string, len(jobs))
+	for _, job := range jobs {
+		info, err := os.Stat(job.Path)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler %s: %w", job.Path, err)
+		}
+		key, err := tmpl.Key(naming.Source{Path: job.Path, Rel: job.Rel, ModTime: info.ModTime()}, env)
+		if err != nil {
+			return nil, err
+		}
+		job.Key = prefix + key
+		if other, dup := sources[job.Key]; dup {
+			return nil, fmt.Errorf("o modelo gera a mesma chave (%s) para %s e %s", job.Key, other, job.Path)
+		}
+		sources[job.Key] = job.Path
+		planned = append(planned, job)
+	}
+	return planned, nil
+}
@@ -0,0 +1,376 @@
+// ui/localfiles.go
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"s3nd-files/internal/filter"
+)
+
+// localFile é um arquivo do painel local
+type localFile struct {
+	Path    string
+	Rel     string
+	Size    int64
+	ModTime time.Time
+}
+
+// localGroup reúne os arquivos de uma pasta adicionada. O grupo sem Root
+// guarda os arquivos adicionados um a um.
+type localGroup struct {
+	Root      string
+	Files     []localFile
+	Excluded  []filter.Entry
+	Size      int64
+	Collapsed bool
+}
+
+// scanRoot varre root com os filtros, sem os caminhos de removed. Pode
+// rodar fora da thread da interface.
+func scanRoot(root string, rules filter.Rules, removed map[string]bool) (*localGroup, error) {
+	g := &localGroup{Root: root}
+	err := filter.Walk(root, rules, time.Now(), func(e filter.Entry) {
+		if e.Excluded() {
+			g.Excluded = append(g.Excluded, e)
+			return
+		}
+		if removed[e.Path] {
+			return
+		}
+		rel, err := filepath.Rel(root, e.Path)
+		if err != nil {
+			rel = e.Path
+		}
+		g.Files = append(g.Files, localFile{Path: e.Path, Rel: rel, Size: e.Size, ModTime: e.ModTime})
+		g.Size += e.Size
+	})
+	return g, err
+}
+
+// Ordem dos arquivos dentro de cada grupo
+type localSort int
+
+const (
+	sortByName localSort = iota
+	sortBySize
+	sortByDate
+)
+
+// Tipos de linha da lista achatada
+type localRowKind int
+
+const (
+	rowGroup localRowKind = iota
+	rowFile
+	rowExcluded
+)
+
+type localRow struct {
+	Kind  localRowKind
+	Group *localGroup
+	Index int
+}
+
+// localFiles é o conteúdo do painel local: os grupos e as linhas que a
+// lista mostra. Cada linha só guarda índices, para escalar a centenas de
+// milhares de arquivos.
+type localFiles struct {
+	groups []*localGroup
+	loose  *localGroup
+	// Caminhos dos avulsos, para não repetir um arquivo já adicionado
+	loosePaths map[string]bool
+	// Arquivos tirados da lista, que uma nova varredura não traz de volta
+	removed map[string]bool
+
+	rows         []localRow
+	sortBy       localSort
+	descending   bool
+	showExcluded bool
+}
+
+func newLocalFiles() *localFiles {
+	return &localFiles{loose: &localGroup{}, loosePaths: make(map[string]bool), removed: make(map[string]bool)}
+}
+
+// Roots retorna as pastas adicionadas
+func (l *localFiles) Roots() []string {
+	roots := make([]string, len(l.groups))
+	for i, g := range l.groups {
+		roots[i] = g.Root
+	}
+	return roots
+}
+
+// Removed retorna uma cópia dos caminhos removidos, para scanRoot
+func (l *localFiles) Removed() map[string]bool {
+	removed := make(map[string]bool, len(l.removed))
+	for path := range l.removed {
+		removed[path] = true
+	}
+	return removed
+}
+
+// SetGroup adiciona o grupo de uma pasta ou substitui a varredura anterior
+func (l *localFiles) SetGroup(g *localGroup) {
+	for i, old := range l.groups {
+		if old.Root == g.Root {
+			g.Collapsed = old.Collapsed
+			l.groups[i] = g
+			l.Rebuild()
+			return
+		}
+	}
+	l.groups = append(l.groups, g)
+	sort.Slice(l.groups, func(i, j int) bool { return l.groups[i].Root < l.groups[j].Root })
+	l.Rebuild()
+}
+
+// AddFiles adiciona arquivos avulsos e refaz as linhas uma vez só. Os
+// que não puderem ser lidos ficam de fora e voltam no erro.
+func (l *localFiles) AddFiles(paths []string) error {
+	var errs []error
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("falha ao ler %s: %w", path, err))
+			continue
+		}
+		delete(l.removed, path)
+		if l.loosePaths[path] {
+			continue
+		}
+		l.loosePaths[path] = true
+		l.loose.Files = append(l.loose.Files, localFile{Path: path, Rel: path, Size: info.Size(), ModTime: info.ModTime()})
+		l.loose.Size += info.Size()
+	}
+	l.Rebuild()
+	return errors.Join(errs...)
+}
+
+// Remove tira os caminhos da lista
+func (l *localFiles) Remove(paths []string) {
+	drop := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		drop[path] = true
+		l.removed[path] = true
+	}
+	for _, g := range l.all() {
+		kept := g.Files[:0]
+		g.Size = 0
+		for _, f := range g.Files {
+			if !drop[f.Path] {
+				kept = append(kept, f)
+				g.Size += f.Size
+			}
+		}
+		if g == l.loose {
+			for path := range drop {
+				delete(l.loosePaths, path)
+			}
+		}
+		g.Files = kept
+	}
+	l.Rebuild()
+}
+
+// RemoveGroup tira uma pasta (ou todos os avulsos) da lista
+func (l *localFiles) RemoveGroup(g *localGroup) {
+	if g == l.loose {
+		l.loose = &localGroup{}
+		l.loosePaths = make(map[string]bool)
+	}
+	for i, old := range l.groups {
+		if old == g {
+			l.groups = append(l.groups[:i], l.groups[i+1:]...)
+			break
+		}
+	}
+	l.Rebuild()
+}
+
+// Clear esvazia a lista
+func (l *localFiles) Clear() {
+	l.groups = nil
+	l.loose = &localGroup{}
+	l.loosePaths = make(map[string]bool)
+	l.removed = make(map[string]bool)
+	l.Rebuild()
+}
+
+// SetSort ordena por by; repetir o mesmo critério inverte a ordem
+func (l *localFiles) SetSort(by localSort) {
+	if l.sortBy == by {
+		l.descending = !l.descending
+	} else {
+		l.sortBy, l.descending = by, by != sortByName
+	}
+	l.Rebuild()
+}
+
+// SetShowExcluded mostra ou esconde os excluídos pelos filtros
+func (l *localFiles) SetShowExcluded(show bool) {
+	l.showExcluded = show
+	l.Rebuild()
+}
+
+// Toggle recolhe ou expande o grupo
+func (l *localFiles) Toggle(g *localGroup) {
+	g.Collapsed = !g.Collapsed
+	l.Rebuild()
+}
+
+// all retorna as pastas seguidas dos avulsos
+func (l *localFiles) all() []*localGroup {
+	return append(l.groups[:len(l.groups):len(l.groups)], l.loose)
+}
+
+// Rebuild ordena os grupos e refaz as linhas
+func (l *localFiles) Rebuild() {
+	l.rows = l.rows[:0]
+	for _, g := range l.all() {
+		if g == l.loose && len(g.Files) == 0 {
+			continue
+		}
+		l.sortGroup(g)
+		l.rows = append(l.rows, localRow{Kind: rowGroup, Group: g})
+		if g.Collapsed {
+			continue
+		}
+		for i := range g.Files {
+			l.rows = append(l.rows, localRow{Kind: rowFile, Group: g, Index: i})
+		}
+		if l.showExcluded {
+			for i := range g.Excluded {
+				l.rows = append(l.rows, localRow{Kind: rowExcluded, Group: g, Index: i})
+			}
+		}
+	}
+}
+
+func (l *localFiles) sortGroup(g *localGroup) {
+	less := func(a, b localFile) bool { return strings.ToLower(a.Rel) < strings.ToLower(b.Rel) }
+	switch l.sortBy {
+	case sortBySize:
+		less = func(a, b localFile) bool { return a.Size < b.Size }
+	case sortByDate:
+		less = func(a, b localFile) bool { return a.ModTime.Before(b.ModTime) }
+	}
+	files := g.Files
+	sort.SliceStable(files, func(i, j int) bool {
+		if l.descending {
+			return less(files[j], files[i])
+		}
+		return less(files[i], files[j])
+	})
+}
+
+// Len é o número de linhas
+func (l *localFiles) Len() int { return len(l.rows) }
+
+// Row retorna a linha id
+func (l *localFiles) Row(id int) (localRow, bool) {
+	if id < 0 || id >= len(l.rows) {
+		return localRow{}, false
+	}
+	return l.rows[id], true
+}
+
+// File retorna o arquivo da linha id, se ela for um arquivo
+func (l *localFiles) File(id int) (localFile, bool) {
+	row, ok := l.Row(id)
+	if !ok || row.Kind != rowFile {
+		return localFile{}, false
+	}
+	return row.Group.Files[row.Index], true
+}
+
+// Text descreve a linha id: o nome e as colunas de tamanho e data
+func (l *localFiles) Text(id int) (name, details string) {
+	row, ok := l.Row(id)
+	if !ok {
+		return "", ""
+	}
+	g := row.Group
+	switch row.Kind {
+	case rowGroup:
+		arrow := "▾"
+		if g.Collapsed {
+			arrow = "▸"
+		}
+		title := "📄 Arquivos avulsos"
+		if g.Root != "" {
+			title = "📁 " + g.Root
+		}
+		details = fmt.Sprintf("%d arquivo(s) · %s", len(g.Files), formatBytes(g.Size))
+		if len(g.Excluded) > 0 {
+			details += fmt.Sprintf(" · %d excluído(s)", len(g.Excluded))
+		}
+		return arrow + " " + title, details
+	case rowExcluded:
+		e := g.Excluded[row.Index]
+		rel, err := filepath.Rel(g.Root, e.Path)
+		if err != nil {
+			rel = e.Path
+		}
+		if e.Dir {
+			rel += string(filepath.Separator)
+		}
+		return "      🚫 " + rel + "  — " + e.Reason, ""
+	}
+	f := g.Files[row.Index]
+	return "      " + f.Rel, fmt.Sprintf("%10s   %s", formatBytes(f.Size), f.ModTime.Format("2006-01-02 15:04"))
+}
+
+// Paths retorna todos os arquivos, sem repetições
+func (l *localFiles) Paths() []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, g := range l.all() {
+		for _, f := range g.Files {
+			if !seen[f.Path] {
+				seen[f.Path] = true
+				paths = append(paths, f.Path)
+			}
+		}
+	}
+	return paths
+}
+
+// RelPaths retorna o caminho relativo de cada arquivo para o envio, a
+// partir do nome da pasta adicionada (avulsos: só o nome)
+func (l *localFiles) RelPaths() map[string]string {
+	rels := make(map[string]string)
+	for _, g := range l.all() {
+		for _, f := range g.Files {
+			if g.Root == "" {
+				rels[f.Path] = filepath.Base(f.Path)
+			} else {
+				rels[f.Path] = filepath.Join(filepath.Base(g.Root), f.Rel)
+			}
+		}
+	}
+	return rels
+}
+
+// Totals conta arquivos, bytes e excluídos de todos os grupos. Como em
+// Paths, um arquivo em mais de um grupo conta uma vez só.
+func (l *localFiles) Totals() (files int, size int64, excluded int) {
+	seen := make(map[string]bool)
+	for _, g := range l.all() {
+		for _, f := range g.Files {
+			if !seen[f.Path] {
+				seen[f.Path] = true
+				files++
+				size += f.Size
+			}
+		}
+		excluded += len(g.Excluded)
+	}
+	return files, size, excluded
+}
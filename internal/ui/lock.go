@@ -0,0 +1,291 @@
+// ui/lock.go
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Opções dos seletores de retenção e legal hold
+const (
+	lockNone = "Sem retenção"
+	lockKeep = "Não alterar"
+	holdOn   = "Ligado"
+	holdOff  = "Desligado"
+)
+
+// formatObjectLock descreve a retenção e o legal hold de um objeto
+func formatObjectLock(lock models.ObjectLock) string {
+	text := "Retenção: nenhuma"
+	if r := lock.Retention; r.Mode != "" {
+		text = fmt.Sprintf("Retenção: %s até %s", r.Mode, r.RetainUntil.Local().Format("2006-01-02 15:04"))
+		if !r.Active(time.Now()) {
+			text += " (expirada)"
+		}
+	}
+	if lock.LegalHold {
+		text += "\nLegal hold: ligado"
+	} else {
+		text += "\nLegal hold: desligado"
+	}
+	return text
+}
+
+// parseRetainUntil aceita uma data (AAAA-MM-DD [HH:MM]) ou um número de dias a partir de agora
+func parseRetainUntil(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if days, err := strconv.Atoi(text); err == nil {
+		if days < 1 {
+			return time.Time{}, fmt.Errorf("a retenção precisa durar ao menos 1 dia")
+		}
+		return time.Now().AddDate(0, 0, days), nil
+	}
+	t, err := parseDateTime(text)
+	if err == nil && t.IsZero() {
+		err = fmt.Errorf("informe a data de retenção ou o número de dias")
+	}
+	return t, err
+}
+
+// retentionFormItems monta os campos de modo e data de retenção. O retorno
+// indica com keep = true que os campos não foram alterados; com allowKeep,
+// o modo também pode ficar "Não alterar".
+func retentionFormItems(initial models.Retention, allowKeep bool) ([]*widget.FormItem, func() (r models.Retention, keep bool, err error)) {
+	options := []string{lockNone}
+	if allowKeep {
+		options = []string{lockKeep, lockNone}
+	}
+	options = append(options, models.LockModes...)
+
+	untilEntry := widget.NewEntry()
+	untilEntry.SetPlaceHolder("AAAA-MM-DD HH:MM ou número de dias")
+	if !initial.RetainUntil.IsZero() {
+		untilEntry.SetText(initial.RetainUntil.Local().Format("2006-01-02 15:04"))
+	}
+	initialUntil := untilEntry.Text
+
+	modeSelect := widget.NewSelect(options, func(mode string) {
+		if mode == lockNone || mode == lockKeep {
+			untilEntry.Disable()
+		} else {
+			untilEntry.Enable()
+		}
+	})
+	switch {
+	case initial.Mode != "":
+		modeSelect.SetSelected(initial.Mode)
+	case allowKeep:
+		modeSelect.SetSelected(lockKeep)
+	default:
+		modeSelect.SetSelected(lockNone)
+	}
+	initialMode := modeSelect.Selected
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Retenção", modeSelect),
+		widget.NewFormItem("Reter até", untilEntry),
+	}
+
+	read := func() (models.Retention, bool, error) {
+		switch {
+		case modeSelect.Selected == lockKeep:
+			return models.Retention{}, true, nil
+		case modeSelect.Selected == initialMode && (untilEntry.Disabled() || untilEntry.Text == initialUntil):
+			// Intocado: mesmo expirada, a retenção atual não é revalidada
+			return initial, true, nil
+		case modeSelect.Selected == lockNone:
+			return models.Retention{}, false, nil
+		}
+
+		until, err := parseRetainUntil(untilEntry.Text)
+		if err != nil {
+			return models.Retention{}, false, err
+		}
+		r := models.Retention{Mode: modeSelect.Selected, RetainUntil: until}
+		// A data é editada em minutos: o mesmo minuto é a mesma retenção
+		if r.Mode == initial.Mode && until.Equal(initial.RetainUntil.Truncate(time.Minute)) {
+			return initial, true, nil
+		}
+		return r, false, r.Validate(time.Now())
+	}
+
+	return items, read
+}
+
+// confirmBypass pede confirmação explícita antes de ignorar uma retenção
+// GOVERNANCE: o botão só é liberado depois de marcar a caixa
+func confirmBypass(w fyne.Window, what string, onConfirm func()) {
+	var d dialog.Dialog
+
+	confirmBtn := widget.NewButton("Ignorar retenção", func() {
+		d.Hide()
+		onConfirm()
+	})
+	confirmBtn.Importance = widget.DangerImportance
+	confirmBtn.Disable()
+
+	check := widget.NewCheck("Entendo que isto ignora a retenção GOVERNANCE", func(on bool) {
+		if on {
+			confirmBtn.Enable()
+		} else {
+			confirmBtn.Disable()
+		}
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel(what+"\n\nIsto exige a permissão s3:BypassGovernanceRetention\n"+
+			"e fica registrado no CloudTrail."),
+		check,
+		container.NewHBox(widget.NewButton("Cancelar", func() { d.Hide() }), confirmBtn),
+	)
+	d = dialog.NewCustomWithoutButtons("⚠️ Ignorar retenção GOVERNANCE", content, w)
+	d.Show()
+}
+
+// showObjectLockDialog mostra e altera a retenção e o legal hold de um objeto
+func showObjectLockDialog(w fyne.Window, client storageBackend, bucket, key string, onSaved func(models.ObjectLock)) {
+	locker, ok := client.(objectLocker)
+	if !ok {
+		dialog.ShowInformation("Object Lock", "Este backend não suporta Object Lock", w)
+		return
+	}
+
+	go func() {
+		current, err := locker.GetObjectLock(context.Background(), bucket, key)
+		runOnUIThread(func() {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			showObjectLockForm(w, locker, bucket, key, current, onSaved)
+		})
+	}()
+}
+
+func showObjectLockForm(w fyne.Window, locker objectLocker, bucket, key string, current models.ObjectLock, onSaved func(models.ObjectLock)) {
+	items, readRetention := retentionFormItems(current.Retention, false)
+	holdSelect := widget.NewSelect([]string{holdOff, holdOn}, nil)
+	if current.LegalHold {
+		holdSelect.SetSelected(holdOn)
+	} else {
+		holdSelect.SetSelected(holdOff)
+	}
+	items = append(items, widget.NewFormItem("Legal hold", holdSelect))
+
+	content := container.NewVBox(
+		widget.NewLabel(key+"\n\n"+formatObjectLock(current)),
+		widget.NewForm(items...),
+	)
+
+	dialog.ShowCustomConfirm("Object Lock", "Salvar", "Cancelar", content, func(save bool) {
+		if !save {
+			return
+		}
+		next, keep, err := readRetention()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		needsBypass := false
+		if !keep {
+			needsBypass, err = models.RetentionChange(current.Retention, next, time.Now())
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+		}
+		hold := holdSelect.Selected == holdOn
+
+		apply := func(bypass bool) {
+			go func() {
+				ctx := context.Background()
+				var err error
+				if !keep {
+					err = locker.PutObjectRetention(ctx, bucket, key, next, bypass)
+				}
+				if err == nil && hold != current.LegalHold {
+					err = locker.PutObjectLegalHold(ctx, bucket, key, hold)
+				}
+				runOnUIThread(func() {
+					if err != nil {
+						dialog.ShowError(err, w)
+						return
+					}
+					if onSaved != nil {
+						onSaved(models.ObjectLock{Retention: next, LegalHold: hold})
+					}
+				})
+			}()
+		}
+
+		if needsBypass {
+			confirmBypass(w, fmt.Sprintf("A nova retenção de %s é mais curta que a atual.", key),
+				func() { apply(true) })
+			return
+		}
+		apply(false)
+	}, w)
+}
+
+// showBulkLockWindow altera retenção e legal hold de keys ou de todo o prefixo
+func showBulkLockWindow(w fyne.Window, client storageBackend, bucket, prefix string, keys []string) {
+	locker, ok := client.(objectLocker)
+	if !ok {
+		dialog.ShowInformation("Object Lock", "Este backend não suporta Object Lock", w)
+		return
+	}
+
+	items, readRetention := retentionFormItems(models.Retention{}, true)
+	holdSelect := widget.NewSelect([]string{lockKeep, holdOn, holdOff}, nil)
+	holdSelect.SetSelected(lockKeep)
+	bypassCheck := widget.NewCheck("Ignorar retenção GOVERNANCE (bypass)", nil)
+	items = append(items,
+		widget.NewFormItem("Legal hold", holdSelect),
+		widget.NewFormItem("", bypassCheck),
+	)
+
+	showBulkWindow(w, client, bucket, prefix, keys, "Object Lock em massa", widget.NewForm(items...),
+		func(bw fyne.Window, start func(bulkAction)) {
+			retention, keepRetention, err := readRetention()
+			if err != nil {
+				dialog.ShowError(err, bw)
+				return
+			}
+			keepHold := holdSelect.Selected == lockKeep
+			if keepRetention && keepHold {
+				dialog.ShowInformation("Object Lock em massa", "Nada a alterar", bw)
+				return
+			}
+			hold := holdSelect.Selected == holdOn
+			bypass := bypassCheck.Checked
+
+			action := func(ctx context.Context, key string) error {
+				if !keepRetention {
+					if err := locker.PutObjectRetention(ctx, bucket, key, retention, bypass); err != nil {
+						return err
+					}
+				}
+				if !keepHold {
+					return locker.PutObjectLegalHold(ctx, bucket, key, hold)
+				}
+				return nil
+			}
+
+			if bypass {
+				confirmBypass(bw, "A retenção GOVERNANCE será ignorada em todos os objetos afetados.",
+					func() { start(action) })
+				return
+			}
+			start(action)
+		})
+}
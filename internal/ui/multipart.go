@@ -0,0 +1,215 @@
+// ui/multipart.go
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// multipartTab lista os envios multipart incompletos do bucket e permite
+// abortá-los um a um ou todos os mais antigos que N dias
+func multipartTab(w fyne.Window, m multipartManager, bucket string) fyne.CanvasObject {
+	var (
+		all      []models.MultipartUpload
+		shown    []models.MultipartUpload
+		selected = -1
+		cancel   context.CancelFunc
+	)
+
+	status := widget.NewLabel("")
+	ownOnly := widget.NewCheck("Somente envios deste app", nil)
+
+	list := widget.NewList(
+		func() int { return len(shown) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id < 0 || id >= len(shown) {
+				return
+			}
+			obj.(*widget.Label).SetText(formatMultipartUpload(shown[id]))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(widget.ListItemID) { selected = -1 }
+
+	applyFilter := func() {
+		shown = shown[:0]
+		var size int64
+		for _, u := range all {
+			if ownOnly.Checked && !u.Own {
+				continue
+			}
+			shown = append(shown, u)
+			size += u.Size
+		}
+		selected = -1
+		list.UnselectAll()
+		list.Refresh()
+		status.SetText(fmt.Sprintf("%d envio(s) incompleto(s), %s em partes", len(shown), formatBytes(size)))
+	}
+	ownOnly.OnChanged = func(bool) { applyFilter() }
+
+	var refreshBtn, abortOneBtn, abortOldBtn *widget.Button
+	setBusy := func(busy bool) {
+		if busy {
+			refreshBtn.SetText("Parar")
+			abortOneBtn.Disable()
+			abortOldBtn.Disable()
+			return
+		}
+		cancel = nil
+		refreshBtn.SetText("Atualizar")
+		abortOneBtn.Enable()
+		abortOldBtn.Enable()
+	}
+
+	reload := func() {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		setBusy(true)
+		status.SetText("Listando envios incompletos...")
+
+		go func() {
+			uploads, err := m.ListMultipartUploads(ctx, bucket)
+			runOnUIThread(func() {
+				setBusy(false)
+				switch {
+				case errors.Is(err, context.Canceled):
+					status.SetText("Listagem interrompida")
+				case err != nil:
+					status.SetText("Falha ao listar envios incompletos")
+					dialog.ShowError(err, w)
+				default:
+					all = uploads
+					applyFilter()
+				}
+			})
+		}()
+	}
+
+	// Abortar os envios informados e recarregar a lista
+	abortBatch := func(batch []models.MultipartUpload) {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		setBusy(true)
+
+		go func() {
+			var failures []string
+			for i, u := range batch {
+				if ctx.Err() != nil {
+					break
+				}
+				if err := m.AbortMultipartUpload(ctx, bucket, u.Key, u.UploadID); err != nil {
+					failures = append(failures, err.Error())
+				}
+
+				n := i + 1
+				runOnUIThread(func() {
+					status.SetText(fmt.Sprintf("Abortando... %d de %d", n, len(batch)))
+				})
+			}
+
+			runOnUIThread(func() {
+				setBusy(false)
+				if len(failures) > 0 {
+					dialog.ShowError(errors.New(strings.Join(failures, "\n")), w)
+				}
+				reload()
+			})
+		}()
+	}
+
+	refreshBtn = widget.NewButton("Atualizar", func() {
+		if cancel != nil {
+			cancel()
+			return
+		}
+		reload()
+	})
+
+	abortOneBtn = widget.NewButton("Abortar selecionado", func() {
+		if selected < 0 || selected >= len(shown) {
+			dialog.ShowInformation("Nenhum envio", "Selecione um envio primeiro", w)
+			return
+		}
+		u := shown[selected]
+		dialog.ShowConfirm("Abortar envio",
+			fmt.Sprintf("Abortar o envio de %s e descartar %d parte(s) (%s)?", u.Key, u.Parts, formatBytes(u.Size)),
+			func(confirm bool) {
+				if confirm {
+					abortBatch([]models.MultipartUpload{u})
+				}
+			}, w)
+	})
+
+	daysEntry := widget.NewEntry()
+	daysEntry.SetText("7")
+	abortOldBtn = widget.NewButton("Abortar antigos", func() {
+		days, err := strconv.Atoi(strings.TrimSpace(daysEntry.Text))
+		if err != nil || days < 0 {
+			dialog.ShowError(fmt.Errorf("informe um número de dias válido"), w)
+			return
+		}
+
+		var batch []models.MultipartUpload
+		var size int64
+		now := time.Now()
+		for _, u := range shown {
+			if u.OlderThan(days, now) {
+				batch = append(batch, u)
+				size += u.Size
+			}
+		}
+		if len(batch) == 0 {
+			dialog.ShowInformation("Abortar antigos",
+				fmt.Sprintf("Nenhum envio listado com mais de %d dia(s)", days), w)
+			return
+		}
+		dialog.ShowConfirm("Abortar antigos",
+			fmt.Sprintf("Abortar %d envio(s) iniciados há mais de %d dia(s) e descartar %s em partes?",
+				len(batch), days, formatBytes(size)),
+			func(confirm bool) {
+				if confirm {
+					abortBatch(batch)
+				}
+			}, w)
+	})
+	abortOldBtn.Importance = widget.DangerImportance
+
+	reload()
+
+	return container.NewBorder(
+		container.NewHBox(refreshBtn, ownOnly),
+		container.NewVBox(
+			status,
+			container.NewHBox(abortOneBtn,
+				widget.NewLabel("Mais antigos que (dias):"), daysEntry, abortOldBtn),
+		),
+		nil, nil,
+		list,
+	)
+}
+
+// formatMultipartUpload descreve um envio incompleto na listagem
+func formatMultipartUpload(u models.MultipartUpload) string {
+	text := fmt.Sprintf("%s  (iniciado em %s · %d parte(s), %s",
+		u.Key, u.Initiated.Local().Format("2006-01-02 15:04"), u.Parts, formatBytes(u.Size))
+	if u.StorageClass != "" && u.StorageClass != "STANDARD" {
+		text += " · " + u.StorageClass
+	}
+	if u.Own {
+		text += " · deste app"
+	}
+	return text + ")"
+}
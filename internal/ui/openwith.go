@@ -0,0 +1,312 @@
+// ui/openwith.go
+package ui
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"s3nd-files/internal/cache"
+	"s3nd-files/internal/envelope"
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Intervalo entre verificações de uma cópia aberta em aplicativo externo e
+// tempo sem alterações após o qual ela deixa de ser observada
+const (
+	openWatchInterval = 2 * time.Second
+	openWatchTimeout  = 8 * time.Hour
+)
+
+// Cópias observadas por watchOpenedFile, por caminho
+var (
+	openWatchesMu sync.Mutex
+	openWatches   = make(map[string]context.CancelFunc)
+)
+
+// stopOpenWatch deixa de observar a cópia em path
+func stopOpenWatch(path string) {
+	openWatchesMu.Lock()
+	defer openWatchesMu.Unlock()
+	if cancel := openWatches[path]; cancel != nil {
+		cancel()
+		delete(openWatches, path)
+	}
+}
+
+// stopOpenWatches encerra todas as observações (ex: ao fechar a janela)
+func stopOpenWatches() {
+	openWatchesMu.Lock()
+	defer openWatchesMu.Unlock()
+	for path, cancel := range openWatches {
+		cancel()
+		delete(openWatches, path)
+	}
+}
+
+// openedBase é a versão do objeto da qual a cópia aberta partiu; é lida
+// pela observação e atualizada pelos envios
+type openedBase struct {
+	mu   sync.Mutex
+	info models.ObjectInfo
+}
+
+func (b *openedBase) get() models.ObjectInfo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.info
+}
+
+func (b *openedBase) set(info models.ObjectInfo) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.info = info
+}
+
+// launchDefaultApp abre path no aplicativo padrão do sistema
+func launchDefaultApp(path string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", path)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("falha ao abrir aplicativo: %w", err)
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// openWithDefaultApp baixa o objeto para o cache, abre a cópia no aplicativo
+// padrão e, quando ela for salva, oferece enviá-la de volta para a mesma chave
+func openWithDefaultApp(w fyne.Window, client storageBackend, files *cache.Cache, bucket string, item models.Item) {
+	path := files.Path(bucket, item.Prefix)
+	if files.Pinned(path) {
+		// Já aberto e observado: abrir de novo ou parar de observar
+		var d dialog.Dialog
+		reopenBtn := widget.NewButton("Abrir de novo", func() {
+			d.Hide()
+			if err := launchDefaultApp(path); err != nil {
+				dialog.ShowError(err, w)
+			}
+		})
+		reopenBtn.Importance = widget.HighImportance
+		d = dialog.NewCustomWithoutButtons("Abrir", container.NewVBox(
+			widget.NewLabel(fmt.Sprintf("%s já está aberto e as alterações estão sendo observadas.", item.Name)),
+			container.NewHBox(
+				widget.NewButton("Cancelar", func() { d.Hide() }),
+				widget.NewButton("Parar de observar", func() {
+					d.Hide()
+					stopOpenWatch(path)
+				}),
+				reopenBtn,
+			),
+		), w)
+		d.Show()
+		return
+	}
+
+	progress := dialog.NewProgressInfinite("Abrir", fmt.Sprintf("Baixando %s...", item.Name), w)
+	progress.Show()
+
+	go func() {
+		ctx := context.Background()
+		var base models.ObjectInfo
+		if stater, ok := client.(objectStater); ok {
+			base, _ = stater.HeadObject(ctx, bucket, item.Prefix)
+		}
+
+		err := os.MkdirAll(filepath.Dir(path), 0o700)
+		if err == nil {
+			err = client.DownloadFile(ctx, bucket, item.Prefix, path)
+		}
+		var stat os.FileInfo
+		if err == nil {
+			stat, err = os.Stat(path)
+		}
+
+		runOnUIThread(func() {
+			progress.Hide()
+			if err != nil {
+				retry := func() { openWithDefaultApp(w, client, files, bucket, item) }
+				if !handleKeyError(w, client, err, retry) {
+					dialog.ShowError(err, w)
+				}
+				return
+			}
+
+			files.Pin(path)
+			go files.Prune()
+			if err := launchDefaultApp(path); err != nil {
+				files.Unpin(path)
+				dialog.ShowError(err, w)
+				return
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			openWatchesMu.Lock()
+			openWatches[path] = cancel
+			openWatchesMu.Unlock()
+			go watchOpenedFile(ctx, w, client, files, bucket, item.Prefix, path, stat, &openedBase{info: base})
+		})
+	}()
+}
+
+// watchOpenedFile observa a cópia aberta e, a cada alteração concluída,
+// pergunta se deve enviá-la. Termina quando a cópia é removida, após
+// openWatchTimeout sem alterações ou quando ctx é cancelado.
+func watchOpenedFile(ctx context.Context, w fyne.Window, client storageBackend, files *cache.Cache, bucket, key, path string, last os.FileInfo, base *openedBase) {
+	defer func() {
+		stopOpenWatch(path)
+		files.Unpin(path)
+	}()
+
+	ticker := time.NewTicker(openWatchInterval)
+	defer ticker.Stop()
+
+	changed := time.Now()
+	var pending os.FileInfo
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		stat, err := os.Stat(path)
+		if err != nil {
+			return
+		}
+		if sameFile(stat, last) {
+			pending = nil
+			if time.Since(changed) > openWatchTimeout {
+				return
+			}
+			continue
+		}
+		changed = time.Now()
+		// Esperar uma verificação sem mudanças: programas gravam em etapas
+		if pending == nil || !sameFile(stat, pending) {
+			pending = stat
+			continue
+		}
+
+		answered := make(chan struct{})
+		runOnUIThread(func() {
+			dialog.ShowConfirm("Arquivo alterado",
+				fmt.Sprintf("%s foi alterado em %s.\n\nEnviar a nova versão para s3://%s/%s?",
+					filepath.Base(path), stat.ModTime().Format("15:04:05"), bucket, key),
+				func(send bool) {
+					if send {
+						uploadOpenedFile(w, client, bucket, key, path, base)
+					}
+					close(answered)
+				}, w)
+		})
+		<-answered
+		last, pending = stat, nil
+	}
+}
+
+func sameFile(a, b os.FileInfo) bool {
+	return a.Size() == b.Size() && a.ModTime().Equal(b.ModTime())
+}
+
+// uploadOpenedFile envia a cópia editada mantendo classe, criptografia e
+// tags do objeto. Se o objeto mudou no bucket desde o download, pede
+// confirmação antes de sobrescrever. base é atualizado após o envio.
+func uploadOpenedFile(w fyne.Window, client storageBackend, bucket, key, path string, base *openedBase) {
+	stater, canStat := client.(objectStater)
+
+	send := func() {
+		progress := dialog.NewProgressInfinite("Enviar", fmt.Sprintf("Enviando %s...", filepath.Base(path)), w)
+		progress.Show()
+
+		go func() {
+			ctx := context.Background()
+			err := client.UploadFile(ctx, bucket, key, path, reuploadOptions(ctx, client, bucket, key, base.get()))
+			if err == nil && canStat {
+				info, _ := stater.HeadObject(ctx, bucket, key)
+				base.set(info)
+			}
+			runOnUIThread(func() {
+				progress.Hide()
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				fyne.CurrentApp().SendNotification(fyne.NewNotification("Arquivo enviado", bucket+"/"+key))
+			})
+		}()
+	}
+
+	etag := base.get().ETag
+	if !canStat || etag == "" {
+		send()
+		return
+	}
+
+	go func() {
+		current, err := stater.HeadObject(context.Background(), bucket, key)
+		runOnUIThread(func() {
+			if err == nil && current.ETag == etag {
+				send()
+				return
+			}
+			dialog.ShowConfirm("Objeto alterado no bucket",
+				fmt.Sprintf("s3://%s/%s mudou desde que foi aberto (ou foi excluído).\n\nSobrescrever com a sua versão?", bucket, key),
+				func(overwrite bool) {
+					if overwrite {
+						send()
+					}
+				}, w)
+		})
+	}()
+}
+
+// reuploadOptions repete no novo envio os cabeçalhos, a classe, a
+// criptografia e as tags do objeto
+func reuploadOptions(ctx context.Context, client storageBackend, bucket, key string, base models.ObjectInfo) models.UploadOptions {
+	opts := models.UploadOptions{ClientSide: base.ClientEncrypted}
+	opts.Headers = models.ObjectHeaders{
+		ContentType:        base.ContentType,
+		CacheControl:       base.CacheControl,
+		ContentEncoding:    base.ContentEncoding,
+		ContentDisposition: base.ContentDisposition,
+		Metadata:           maps.Clone(base.Metadata),
+	}
+	// O envelope antigo não vale para o novo conteúdo; o envio grava o seu
+	maps.DeleteFunc(opts.Headers.Metadata, func(k, _ string) bool {
+		return strings.HasPrefix(k, envelope.MetaAlgorithm)
+	})
+	if base.StorageClass != "STANDARD" {
+		opts.StorageClass = base.StorageClass
+	}
+	if cfg, ok := client.(sseConfigurer); ok && base.Encryption != models.SSENone {
+		enc := models.Encryption{Mode: base.Encryption, KMSKeyID: base.KMSKeyID}
+		if enc.Mode == models.SSEC {
+			enc.CustomerKey = cfg.Encryption().CustomerKey
+		}
+		opts.Encryption = &enc
+	}
+	if tr, ok := client.(tagReader); ok {
+		opts.Tags, _ = tr.GetObjectTags(ctx, bucket, key)
+	}
+	return opts
+}
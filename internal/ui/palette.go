@@ -0,0 +1,77 @@
+// ui/palette.go
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// command é uma ação da janela principal listada na paleta de comandos
+type command struct {
+	Name     string
+	Shortcut string
+	Run      func()
+}
+
+// showCommandPalette lista commands com um filtro por nome. Enter executa
+// o primeiro resultado e o clique executa o comando da linha.
+func showCommandPalette(w fyne.Window, commands []command) {
+	shown := commands
+	var d dialog.Dialog
+
+	run := func(c command) {
+		d.Hide()
+		c.Run()
+	}
+
+	list := widget.NewList(
+		func() int { return len(shown) },
+		func() fyne.CanvasObject {
+			shortcut := widget.NewLabel("")
+			shortcut.Importance = widget.LowImportance
+			return container.NewBorder(nil, nil, nil, shortcut, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id < 0 || id >= len(shown) {
+				return
+			}
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(shown[id].Name)
+			row.Objects[1].(*widget.Label).SetText(shown[id].Shortcut)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		if id >= 0 && id < len(shown) {
+			run(shown[id])
+		}
+	}
+
+	filter := widget.NewEntry()
+	filter.SetPlaceHolder("Digite para filtrar os comandos...")
+	filter.OnChanged = func(text string) {
+		text = strings.ToLower(strings.TrimSpace(text))
+		shown = nil
+		for _, c := range commands {
+			if strings.Contains(strings.ToLower(c.Name), text) {
+				shown = append(shown, c)
+			}
+		}
+		list.UnselectAll()
+		list.Refresh()
+	}
+	filter.OnSubmitted = func(string) {
+		if len(shown) > 0 {
+			run(shown[0])
+		}
+	}
+
+	content := container.NewBorder(filter, nil, nil, nil, list)
+	d = dialog.NewCustom("Comandos", "Fechar", content, w)
+	d.Resize(fyne.NewSize(520, 420))
+	d.Show()
+	w.Canvas().Focus(filter)
+}
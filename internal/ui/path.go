@@ -0,0 +1,94 @@
+// ui/path.go
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// location é um ponto de navegação do painel S3. Bucket vazio é a lista de buckets.
+type location struct {
+	Bucket string
+	Prefix string
+}
+
+// String formata a localização como s3://bucket/prefixo/
+func (l location) String() string {
+	if l.Bucket == "" {
+		return "s3://"
+	}
+	return "s3://" + l.Bucket + "/" + l.Prefix
+}
+
+// parseS3Path aceita "s3://bucket/pasta/", "bucket/pasta" ou "/" (lista de
+// buckets). O prefixo resultante sempre termina em "/" quando não é vazio,
+// pois o endereço aponta para uma pasta.
+func parseS3Path(text string) (location, error) {
+	text = strings.TrimSpace(text)
+	text = strings.TrimPrefix(text, "s3://")
+	text = strings.TrimLeft(text, "/")
+
+	if text == "" {
+		return location{}, nil
+	}
+
+	bucket, prefix, _ := strings.Cut(text, "/")
+	if bucket == "" || strings.ContainsAny(bucket, " \t") {
+		return location{}, fmt.Errorf("endereço inválido: %s", text)
+	}
+
+	prefix = strings.TrimLeft(prefix, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return location{Bucket: bucket, Prefix: prefix}, nil
+}
+
+// navHistory guarda o histórico de navegação para voltar/avançar
+type navHistory struct {
+	entries []location
+	index   int
+}
+
+// Push registra uma nova localização, descartando o que estava à frente
+func (h *navHistory) Push(loc location) {
+	if len(h.entries) > 0 && h.entries[h.index] == loc {
+		return
+	}
+	if len(h.entries) > 0 {
+		h.entries = h.entries[:h.index+1]
+	}
+	h.entries = append(h.entries, loc)
+	h.index = len(h.entries) - 1
+}
+
+// Reset limpa o histórico (ex: ao conectar em outro backend)
+func (h *navHistory) Reset() {
+	h.entries = nil
+	h.index = 0
+}
+
+func (h *navHistory) CanBack() bool {
+	return h.index > 0
+}
+
+func (h *navHistory) CanForward() bool {
+	return h.index < len(h.entries)-1
+}
+
+func (h *navHistory) Back() (location, bool) {
+	if !h.CanBack() {
+		return location{}, false
+	}
+	h.index--
+	return h.entries[h.index], true
+}
+
+func (h *navHistory) Forward() (location, bool) {
+	if !h.CanForward() {
+		return location{}, false
+	}
+	h.index++
+	return h.entries[h.index], true
+}
@@ -0,0 +1,457 @@
+// ui/preview.go
+package ui
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"s3nd-files/internal/cache"
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Quanto ler de cada tipo de objeto para a pré-visualização
+const (
+	previewTextLimit  = 256 << 10
+	previewDataLimit  = 2 << 20
+	previewImageLimit = 20 << 20
+	previewCSVRows    = 1000
+	thumbnailSize     = 512
+	thumbnailCacheMax = 64
+	// Imagens acima disto (em pixels) não são decodificadas: um arquivo
+	// pequeno pode declarar dimensões que ocupariam gigabytes de memória
+	previewMaxPixels = 50_000_000
+)
+
+// previewKind é a forma de exibir um objeto
+type previewKind int
+
+const (
+	previewNone previewKind = iota
+	previewText
+	previewJSON
+	previewCSV
+	previewMarkdown
+	previewImage
+)
+
+// previewKindFor escolhe a exibição pela extensão de name (sem .gz) e,
+// na falta dela, pelo Content-Type
+func previewKindFor(name, contentType string) previewKind {
+	switch strings.ToLower(path.Ext(strings.TrimSuffix(strings.ToLower(name), ".gz"))) {
+	case ".png", ".jpg", ".jpeg", ".gif":
+		return previewImage
+	case ".json", ".jsonl", ".ndjson", ".geojson":
+		return previewJSON
+	case ".csv", ".tsv":
+		return previewCSV
+	case ".md", ".markdown":
+		return previewMarkdown
+	case ".txt", ".log", ".out", ".yaml", ".yml", ".xml", ".html", ".ini", ".conf", ".toml",
+		".sh", ".py", ".go", ".js", ".ts", ".sql", ".env", ".properties":
+		return previewText
+	}
+
+	contentType, _, _ = strings.Cut(contentType, ";")
+	switch contentType = strings.TrimSpace(contentType); {
+	case contentType == "image/png", contentType == "image/jpeg", contentType == "image/gif":
+		return previewImage
+	case contentType == "application/json":
+		return previewJSON
+	case contentType == "text/csv":
+		return previewCSV
+	case contentType == "text/markdown":
+		return previewMarkdown
+	case strings.HasPrefix(contentType, "text/"):
+		return previewText
+	}
+	return previewNone
+}
+
+// previewLimit é quanto ler de um objeto de cada tipo
+func previewLimit(kind previewKind) int64 {
+	switch kind {
+	case previewImage:
+		return previewImageLimit
+	case previewJSON, previewCSV:
+		return previewDataLimit
+	default:
+		return previewTextLimit
+	}
+}
+
+// previewPane mostra o conteúdo do arquivo selecionado ao lado da listagem
+type previewPane struct {
+	w         fyne.Window
+	files     *cache.Cache
+	onDetails func(bucket string, item models.Item)
+
+	title   *widget.Label
+	note    *widget.Label
+	body    *fyne.Container
+	actions *fyne.Container
+	root    fyne.CanvasObject
+
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	thumbs map[string]cachedThumb
+}
+
+// cachedThumb é uma miniatura já decodificada e sua descrição
+type cachedThumb struct {
+	img  image.Image
+	note string
+}
+
+// newPreviewPane cria o painel; onDetails abre as informações do arquivo.
+// Sem files (cache indisponível), não há "Abrir com".
+func newPreviewPane(w fyne.Window, files *cache.Cache, onDetails func(bucket string, item models.Item)) *previewPane {
+	p := &previewPane{
+		w:         w,
+		files:     files,
+		onDetails: onDetails,
+		title:     widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		note:      widget.NewLabel(""),
+		body:      container.NewStack(),
+		actions:   container.NewHBox(),
+		thumbs:    make(map[string]cachedThumb),
+	}
+	p.title.Truncation = fyne.TextTruncateEllipsis
+	p.root = container.NewBorder(
+		container.NewVBox(p.title, p.actions),
+		p.note, nil, nil,
+		p.body,
+	)
+	p.Clear()
+	return p
+}
+
+// Supports informa se o backend permite leituras parciais
+func (p *previewPane) Supports(client storageBackend) bool {
+	_, ok := client.(objectReader)
+	return ok
+}
+
+// Clear volta o painel ao estado inicial
+func (p *previewPane) Clear() {
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+	p.title.SetText("Pré-visualização")
+	p.note.SetText("")
+	p.actions.Objects = nil
+	p.actions.Refresh()
+	p.setBody(container.NewCenter(widget.NewLabel("Selecione um arquivo")))
+}
+
+func (p *previewPane) setBody(obj fyne.CanvasObject) {
+	p.body.Objects = []fyne.CanvasObject{obj}
+	p.body.Refresh()
+}
+
+// Show lê o início do objeto e o exibe conforme o tipo
+func (p *previewPane) Show(client storageBackend, bucket string, item models.Item) {
+	reader, ok := client.(objectReader)
+	if !ok {
+		return
+	}
+	if p.cancel != nil {
+		p.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.title.SetText(item.Name)
+	p.note.SetText("")
+	p.actions.Objects = []fyne.CanvasObject{
+		widget.NewButton("ℹ️ Detalhes", func() { p.onDetails(bucket, item) }),
+		widget.NewButton("⬇️ Baixar", func() { showDownloadDialog(p.w, client, bucket, item) }),
+	}
+	if _, ok := client.(objectSaver); ok && previewKindFor(item.Name, "") != previewImage {
+		p.actions.Add(widget.NewButton("✏️ Editar", func() { showTextEditor(p.w, client, bucket, item.Prefix) }))
+	}
+	if p.files != nil {
+		p.actions.Add(widget.NewButton("📂 Abrir com…", func() { openWithDefaultApp(p.w, client, p.files, bucket, item) }))
+	}
+	p.actions.Refresh()
+	p.setBody(container.NewCenter(widget.NewLabel("Carregando...")))
+
+	go func() {
+		content, note, err := p.load(ctx, reader, bucket, item)
+		if ctx.Err() != nil {
+			return
+		}
+		runOnUIThread(func() {
+			// Outra seleção pode ter chegado enquanto o resultado esperava a thread da interface
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				if handleKeyError(p.w, client, err, func() { p.Show(client, bucket, item) }) {
+					p.setBody(container.NewCenter(widget.NewLabel("Chave necessária para ler o objeto")))
+					return
+				}
+				p.setBody(container.NewCenter(widget.NewLabel(err.Error())))
+				return
+			}
+			p.note.SetText(note)
+			p.setBody(content)
+		})
+	}()
+}
+
+// load lê o objeto e monta a exibição. note descreve cortes no conteúdo.
+func (p *previewPane) load(ctx context.Context, reader objectReader, bucket string, item models.Item) (fyne.CanvasObject, string, error) {
+	kind := previewKindFor(item.Name, "")
+	limit := previewLimit(kind)
+
+	rc, info, err := reader.OpenObject(ctx, bucket, item.Prefix, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	if kind == previewNone {
+		kind = previewKindFor(item.Name, info.ContentType)
+		// O tipo só apareceu na resposta e pede mais do que foi lido
+		if need := previewLimit(kind); need > limit && info.Size > limit {
+			rc.Close()
+			limit = need
+			if rc, info, err = reader.OpenObject(ctx, bucket, item.Prefix, limit); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	defer rc.Close()
+
+	data, truncated, err := readPreview(rc, info, item.Name, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	note := ""
+	if truncated {
+		note = fmt.Sprintf("Mostrando os primeiros %s de %s", formatBytes(int64(len(data))), formatBytes(info.Size))
+	}
+
+	if kind == previewNone {
+		if !looksLikeText(data) {
+			return container.NewCenter(widget.NewLabel("Sem pré-visualização para este tipo de arquivo")), "", nil
+		}
+		kind = previewText
+	}
+
+	switch kind {
+	case previewImage:
+		if truncated {
+			return container.NewCenter(widget.NewLabel(
+				fmt.Sprintf("Imagem grande demais para pré-visualizar (%s)", formatBytes(info.Size)))), "", nil
+		}
+		return p.imagePreview(bucket+"/"+item.Prefix+"@"+info.ETag, data)
+	case previewJSON:
+		if !truncated {
+			var pretty bytes.Buffer
+			if err := json.Indent(&pretty, data, "", "  "); err == nil {
+				return textPreview(pretty.Bytes()), note, nil
+			}
+		}
+		return textPreview(data), note, nil
+	case previewCSV:
+		return csvPreview(data, truncated, strings.Contains(strings.ToLower(item.Name), ".tsv")), note, nil
+	case previewMarkdown:
+		if !truncated {
+			md := widget.NewRichTextFromMarkdown(string(data))
+			md.Wrapping = fyne.TextWrapWord
+			return container.NewScroll(md), note, nil
+		}
+		return textPreview(data), note, nil
+	default:
+		if !looksLikeText(data) {
+			return container.NewCenter(widget.NewLabel("O conteúdo não parece ser texto")), "", nil
+		}
+		return textPreview(data), note, nil
+	}
+}
+
+// readPreview lê até limit bytes, descomprimindo gzip no caminho
+func readPreview(rc io.Reader, info models.ObjectInfo, name string, limit int64) ([]byte, bool, error) {
+	gzipped := info.ContentEncoding == "gzip" || strings.HasSuffix(strings.ToLower(name), ".gz")
+	if !gzipped {
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, false, fmt.Errorf("falha ao ler objeto: %w", err)
+		}
+		return data, info.Size > int64(len(data)), nil
+	}
+
+	zr, err := gzip.NewReader(rc)
+	if err != nil {
+		return nil, false, fmt.Errorf("falha ao descomprimir objeto: %w", err)
+	}
+	data, err := io.ReadAll(io.LimitReader(zr, limit+1))
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		// Só o início do arquivo comprimido foi lido
+		return data, true, nil
+	case err != nil:
+		return nil, false, fmt.Errorf("falha ao descomprimir objeto: %w", err)
+	case int64(len(data)) > limit:
+		return data[:limit], true, nil
+	}
+	return data, false, nil
+}
+
+// looksLikeText aceita UTF-8 válido, tolerando um caractere cortado no fim
+func looksLikeText(data []byte) bool {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return false
+	}
+	for i := 0; i < utf8.UTFMax && len(data) > 0; i++ {
+		if utf8.Valid(data) {
+			return true
+		}
+		data = data[:len(data)-1]
+	}
+	return utf8.Valid(data)
+}
+
+// textPreview mostra texto em fonte monoespaçada
+func textPreview(data []byte) fyne.CanvasObject {
+	grid := widget.NewTextGridFromString(strings.ToValidUTF8(string(data), "�"))
+	return container.NewScroll(grid)
+}
+
+// csvPreview mostra as primeiras linhas de um CSV em tabela. Com truncated,
+// a última linha (provavelmente cortada) é descartada.
+func csvPreview(data []byte, truncated, tabs bool) fyne.CanvasObject {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	if tabs {
+		r.Comma = '\t'
+	}
+
+	var rows [][]string
+	columns := 0
+	for len(rows) <= previewCSVRows {
+		record, err := r.Read()
+		if err != nil {
+			break
+		}
+		rows = append(rows, record)
+		columns = max(columns, len(record))
+	}
+	if truncated && len(rows) > 1 && len(rows) <= previewCSVRows {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) > previewCSVRows {
+		rows = rows[:previewCSVRows]
+	}
+	if len(rows) == 0 {
+		return textPreview(data)
+	}
+
+	table := widget.NewTable(
+		func() (int, int) { return len(rows), columns },
+		func() fyne.CanvasObject {
+			l := widget.NewLabel("")
+			l.Truncation = fyne.TextTruncateEllipsis
+			return l
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			text := ""
+			if id.Col < len(rows[id.Row]) {
+				text = rows[id.Row][id.Col]
+			}
+			label := obj.(*widget.Label)
+			label.TextStyle.Bold = id.Row == 0
+			label.SetText(text)
+		},
+	)
+	for c := 0; c < columns; c++ {
+		table.SetColumnWidth(c, 140)
+	}
+	return table
+}
+
+// imagePreview decodifica a imagem e mostra uma miniatura, guardada em
+// memória para seleções repetidas
+func (p *previewPane) imagePreview(cacheKey string, data []byte) (fyne.CanvasObject, string, error) {
+	p.mu.Lock()
+	thumb, ok := p.thumbs[cacheKey]
+	p.mu.Unlock()
+
+	if !ok {
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, "", fmt.Errorf("falha ao decodificar imagem: %w", err)
+		}
+		if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > previewMaxPixels {
+			return nil, "", fmt.Errorf("imagem grande demais para pré-visualizar (%d×%d)", cfg.Width, cfg.Height)
+		}
+
+		img, format, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, "", fmt.Errorf("falha ao decodificar imagem: %w", err)
+		}
+		b := img.Bounds()
+		thumb = cachedThumb{
+			img:  thumbnail(img, thumbnailSize),
+			note: fmt.Sprintf("%s, %d×%d", strings.ToUpper(format), b.Dx(), b.Dy()),
+		}
+
+		p.mu.Lock()
+		if len(p.thumbs) >= thumbnailCacheMax {
+			clear(p.thumbs)
+		}
+		p.thumbs[cacheKey] = thumb
+		p.mu.Unlock()
+	}
+
+	c := canvas.NewImageFromImage(thumb.img)
+	c.FillMode = canvas.ImageFillContain
+	c.SetMinSize(fyne.NewSize(200, 200))
+	return c, thumb.note, nil
+}
+
+// thumbnail reduz img para caber em size×size (vizinho mais próximo)
+func thumbnail(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return img
+	}
+
+	tw, th := size, h*size/w
+	if h > w {
+		tw, th = w*size/h, size
+	}
+	tw, th = max(tw, 1), max(th, 1)
+
+	out := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		sy := b.Min.Y + y*h/th
+		for x := 0; x < tw; x++ {
+			out.Set(x, y, img.At(b.Min.X+x*w/tw, sy))
+		}
+	}
+	return out
+}
@@ -0,0 +1,247 @@
+// ui/recover.go
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showRecoverWindow abre a tela "recuperar excluídos": encontra chaves sob
+// um prefixo que foram excluídas (ou alteradas depois de um instante) e as
+// recupera em lote
+func showRecoverWindow(w fyne.Window, client storageBackend, bucket, prefix string) {
+	r, ok := client.(recoverer)
+	if !ok {
+		dialog.ShowInformation("Recuperar excluídos",
+			"Este backend não suporta versionamento", w)
+		return
+	}
+
+	rw := fyne.CurrentApp().NewWindow(fmt.Sprintf("Recuperar excluídos em %s", bucket))
+	rw.Resize(fyne.NewSize(800, 550))
+
+	prefixEntry := widget.NewEntry()
+	prefixEntry.SetText(prefix)
+
+	asOfEntry := widget.NewEntry()
+	asOfEntry.SetPlaceHolder("vazio = desfazer exclusões; ou AAAA-MM-DD HH:MM")
+
+	form := widget.NewForm(
+		widget.NewFormItem("Prefixo", prefixEntry),
+		widget.NewFormItem("Estado em", asOfEntry),
+	)
+
+	var (
+		candidates []models.RecoveryCandidate
+		selected   = -1
+		cancel     context.CancelFunc
+	)
+
+	status := widget.NewLabel("")
+	list := widget.NewList(
+		func() int { return len(candidates) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id < 0 || id >= len(candidates) {
+				return
+			}
+			obj.(*widget.Label).SetText(formatRecoveryCandidate(candidates[id]))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(widget.ListItemID) { selected = -1 }
+
+	var scanBtn, recoverOneBtn, recoverAllBtn *widget.Button
+	setBusy := func(busy bool) {
+		if busy {
+			scanBtn.SetText("Parar")
+			recoverOneBtn.Disable()
+			recoverAllBtn.Disable()
+			return
+		}
+		cancel = nil
+		scanBtn.SetText("Procurar")
+		recoverOneBtn.Enable()
+		recoverAllBtn.Enable()
+	}
+
+	scanBtn = widget.NewButton("Procurar", func() {
+		if cancel != nil {
+			cancel()
+			return
+		}
+
+		asOf, err := parseDateTime(asOfEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, rw)
+			return
+		}
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		candidates = nil
+		selected = -1
+		list.UnselectAll()
+		list.Refresh()
+		setBusy(true)
+		status.SetText("Listando versões...")
+
+		scanPrefix := prefixEntry.Text
+		go func() {
+			var (
+				versions   []models.ObjectVersion
+				lastUpdate time.Time
+			)
+			err := r.WalkObjectVersions(ctx, bucket, scanPrefix, func(v models.ObjectVersion) error {
+				versions = append(versions, v)
+				if time.Since(lastUpdate) >= searchRefreshInterval {
+					lastUpdate = time.Now()
+					count := len(versions)
+					runOnUIThread(func() {
+						status.SetText(fmt.Sprintf("Listando versões... %d encontradas", count))
+					})
+				}
+				return nil
+			})
+
+			var plan []models.RecoveryCandidate
+			if err == nil {
+				plan = models.PlanRecovery(versions, asOf)
+			}
+
+			runOnUIThread(func() {
+				setBusy(false)
+				switch {
+				case errors.Is(err, context.Canceled):
+					status.SetText("Busca interrompida")
+				case err != nil:
+					status.SetText("Falha ao listar versões")
+					dialog.ShowError(err, rw)
+				default:
+					candidates = plan
+					list.Refresh()
+					status.SetText(fmt.Sprintf("%d chave(s) recuperável(is) em %d versão(ões)",
+						len(candidates), len(versions)))
+				}
+			})
+		}()
+	})
+	scanBtn.Importance = widget.HighImportance
+
+	// Recuperar os candidatos informados, removendo da lista os que deram certo
+	recoverBatch := func(batch []models.RecoveryCandidate) {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		setBusy(true)
+
+		go func() {
+			done := make(map[string]bool)
+			var failures []string
+
+			for i, c := range batch {
+				if ctx.Err() != nil {
+					break
+				}
+				if err := r.Recover(ctx, bucket, c); err != nil {
+					failures = append(failures, fmt.Sprintf("%s: %v", c.Key, err))
+				} else {
+					done[c.Key] = true
+				}
+
+				n := i + 1
+				runOnUIThread(func() {
+					status.SetText(fmt.Sprintf("Recuperando... %d de %d", n, len(batch)))
+				})
+			}
+
+			runOnUIThread(func() {
+				setBusy(false)
+				remaining := candidates[:0]
+				for _, c := range candidates {
+					if !done[c.Key] {
+						remaining = append(remaining, c)
+					}
+				}
+				candidates = remaining
+				selected = -1
+				list.UnselectAll()
+				list.Refresh()
+
+				status.SetText(fmt.Sprintf("%d chave(s) recuperada(s), %d falha(s)", len(done), len(failures)))
+				if len(failures) > 0 {
+					dialog.ShowError(errors.New(strings.Join(failures, "\n")), rw)
+				}
+			})
+		}()
+	}
+
+	recoverOneBtn = widget.NewButton("Recuperar selecionado", func() {
+		if selected < 0 || selected >= len(candidates) {
+			dialog.ShowInformation("Nenhuma chave", "Selecione uma chave primeiro", rw)
+			return
+		}
+		recoverBatch([]models.RecoveryCandidate{candidates[selected]})
+	})
+
+	recoverAllBtn = widget.NewButton("Recuperar todos", func() {
+		if len(candidates) == 0 {
+			return
+		}
+		dialog.ShowConfirm("Recuperar todos",
+			fmt.Sprintf("Recuperar %d chave(s) em %s?", len(candidates), bucket),
+			func(confirm bool) {
+				if confirm {
+					recoverBatch(append([]models.RecoveryCandidate(nil), candidates...))
+				}
+			}, rw)
+	})
+
+	rw.SetOnClosed(func() {
+		if cancel != nil {
+			cancel()
+		}
+	})
+
+	rw.SetContent(container.NewBorder(
+		container.NewVBox(form, scanBtn),
+		container.NewVBox(status, container.NewHBox(recoverOneBtn, recoverAllBtn)),
+		nil, nil,
+		list,
+	))
+	rw.Show()
+}
+
+// formatRecoveryCandidate descreve a chave e o que será feito para recuperá-la
+func formatRecoveryCandidate(c models.RecoveryCandidate) string {
+	action := fmt.Sprintf("remover %d marcador(es)", len(c.Newer))
+	if !c.OnlyDeleteMarkers() {
+		action = "copiar versão como atual"
+	}
+	return fmt.Sprintf("%s  (versão de %s, %s; %s)", c.Key,
+		c.Target.LastModified.Local().Format("2006-01-02 15:04"),
+		formatBytes(c.Target.Size), action)
+}
+
+// parseDateTime aceita AAAA-MM-DD ou AAAA-MM-DD HH:MM (horário local)
+func parseDateTime(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("data inválida (use AAAA-MM-DD HH:MM): %s", text)
+}
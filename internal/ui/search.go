@@ -0,0 +1,322 @@
+// ui/search.go
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Intervalo entre atualizações da lista de resultados
+const searchRefreshInterval = 200 * time.Millisecond
+
+// showSearchWindow abre uma janela para buscar objetos recursivamente sob
+// um prefixo, filtrando por nome, tamanho, data, classe e tags
+func showSearchWindow(client storageBackend, bucket, prefix string) {
+	sw := fyne.CurrentApp().NewWindow(fmt.Sprintf("Buscar em %s", bucket))
+	sw.Resize(fyne.NewSize(800, 600))
+
+	prefixEntry := widget.NewEntry()
+	prefixEntry.SetText(prefix)
+
+	patternEntry := widget.NewEntry()
+	patternEntry.SetPlaceHolder("*.csv ou logs/2024-*/*.gz")
+	regexCheck := widget.NewCheck("Expressão regular", nil)
+
+	minSizeEntry := widget.NewEntry()
+	minSizeEntry.SetPlaceHolder("ex: 10MB")
+	maxSizeEntry := widget.NewEntry()
+	maxSizeEntry.SetPlaceHolder("ex: 1GB")
+
+	afterEntry := widget.NewEntry()
+	afterEntry.SetPlaceHolder("AAAA-MM-DD")
+	beforeEntry := widget.NewEntry()
+	beforeEntry.SetPlaceHolder("AAAA-MM-DD")
+
+	classEntry := widget.NewEntry()
+	classEntry.SetPlaceHolder("STANDARD, GLACIER...")
+
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetPlaceHolder("chave=valor, outra-chave")
+
+	form := widget.NewForm(
+		widget.NewFormItem("Prefixo", prefixEntry),
+		widget.NewFormItem("Nome", container.NewBorder(nil, nil, nil, regexCheck, patternEntry)),
+		widget.NewFormItem("Tamanho", container.NewGridWithColumns(2, minSizeEntry, maxSizeEntry)),
+		widget.NewFormItem("Modificado", container.NewGridWithColumns(2, afterEntry, beforeEntry)),
+		widget.NewFormItem("Classe", classEntry),
+		widget.NewFormItem("Tags", tagsEntry),
+	)
+
+	var (
+		results  []models.Object
+		selected = -1
+		cancel   context.CancelFunc
+	)
+
+	status := widget.NewLabel("")
+	resultList := widget.NewList(
+		func() int { return len(results) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id < 0 || id >= len(results) {
+				return
+			}
+			r := results[id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("%s  (%s, %s)",
+				r.Key, formatBytes(r.Size), r.LastModified.Local().Format("2006-01-02 15:04")))
+		},
+	)
+	resultList.OnSelected = func(id widget.ListItemID) { selected = id }
+	resultList.OnUnselected = func(widget.ListItemID) { selected = -1 }
+
+	selectedObject := func() (models.Object, bool) {
+		if selected < 0 || selected >= len(results) {
+			dialog.ShowInformation("Nenhum resultado", "Selecione um resultado primeiro", sw)
+			return models.Object{}, false
+		}
+		return results[selected], true
+	}
+
+	var searchBtn *widget.Button
+	finish := func(text string) {
+		cancel = nil
+		searchBtn.SetText("Buscar")
+		status.SetText(text)
+	}
+
+	searchBtn = widget.NewButton("Buscar", func() {
+		if cancel != nil {
+			cancel()
+			return
+		}
+
+		query, err := parseSearchQuery(patternEntry.Text, regexCheck.Checked,
+			minSizeEntry.Text, maxSizeEntry.Text, afterEntry.Text, beforeEntry.Text,
+			classEntry.Text, tagsEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, sw)
+			return
+		}
+		query.Prefix = prefixEntry.Text
+		match, err := query.Matcher()
+		if err != nil {
+			dialog.ShowError(err, sw)
+			return
+		}
+
+		tags, canReadTags := client.(tagReader)
+		if len(query.Tags) > 0 && !canReadTags {
+			dialog.ShowError(fmt.Errorf("este backend não suporta tags"), sw)
+			return
+		}
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		results = nil
+		selected = -1
+		resultList.UnselectAll()
+		resultList.Refresh()
+		searchBtn.SetText("Parar")
+		status.SetText("Buscando...")
+
+		searchPrefix := query.Prefix
+		go func() {
+			var (
+				pending    []models.Object
+				scanned    int
+				lastUpdate time.Time
+			)
+			flush := func() {
+				batch := pending
+				pending = nil
+				count := scanned
+				runOnUIThread(func() {
+					results = append(results, batch...)
+					status.SetText(fmt.Sprintf("%d resultado(s) em %d objeto(s) verificados...", len(results), count))
+					resultList.Refresh()
+				})
+			}
+
+			err := client.WalkObjects(ctx, bucket, searchPrefix, func(obj models.Object) error {
+				scanned++
+				if match(obj) {
+					ok := true
+					if len(query.Tags) > 0 {
+						objTags, err := tags.GetObjectTags(ctx, bucket, obj.Key)
+						if err != nil {
+							return err
+						}
+						ok = query.MatchTags(objTags)
+					}
+					if ok {
+						pending = append(pending, obj)
+					}
+				}
+				if time.Since(lastUpdate) >= searchRefreshInterval {
+					lastUpdate = time.Now()
+					flush()
+				}
+				return nil
+			})
+			flush()
+
+			runOnUIThread(func() {
+				switch {
+				case errors.Is(err, context.Canceled):
+					finish(fmt.Sprintf("Busca interrompida: %d resultado(s) em %d objeto(s)", len(results), scanned))
+				case err != nil:
+					finish("Falha na busca")
+					dialog.ShowError(err, sw)
+				default:
+					finish(fmt.Sprintf("%d resultado(s) em %d objeto(s)", len(results), scanned))
+				}
+			})
+		}()
+	})
+	searchBtn.Importance = widget.HighImportance
+
+	downloadBtn := widget.NewButton("Baixar", func() {
+		obj, ok := selectedObject()
+		if !ok {
+			return
+		}
+		showDownloadDialog(sw, client, bucket, models.Item{
+			Name:   path.Base(obj.Key),
+			Type:   models.File,
+			Prefix: obj.Key,
+		})
+	})
+
+	deleteBtn := widget.NewButton("Excluir", func() {
+		obj, ok := selectedObject()
+		if !ok {
+			return
+		}
+		dialog.ShowConfirm("Excluir objeto",
+			fmt.Sprintf("Excluir permanentemente %s/%s?", bucket, obj.Key),
+			func(confirm bool) {
+				if !confirm {
+					return
+				}
+				go func() {
+					err := client.DeleteObject(context.Background(), bucket, obj.Key)
+					runOnUIThread(func() {
+						if err != nil {
+							dialog.ShowError(err, sw)
+							return
+						}
+						for i := range results {
+							if results[i].Key == obj.Key {
+								results = append(results[:i], results[i+1:]...)
+								break
+							}
+						}
+						resultList.UnselectAll()
+						resultList.Refresh()
+					})
+				}()
+			}, sw)
+	})
+
+	shareBtn := widget.NewButton("Compartilhar", func() {
+		obj, ok := selectedObject()
+		if !ok {
+			return
+		}
+		showShareDialog(sw, client, bucket, obj.Key)
+	})
+
+	sw.SetOnClosed(func() {
+		if cancel != nil {
+			cancel()
+		}
+	})
+
+	sw.SetContent(container.NewBorder(
+		container.NewVBox(form, searchBtn),
+		container.NewVBox(status, container.NewHBox(downloadBtn, deleteBtn, shareBtn)),
+		nil, nil,
+		resultList,
+	))
+	sw.Show()
+}
+
+// parseSearchQuery converte os campos do formulário de busca em uma consulta
+func parseSearchQuery(pattern string, regex bool, minSize, maxSize, after, before, class, tags string) (models.SearchQuery, error) {
+	q := models.SearchQuery{
+		Pattern:      strings.TrimSpace(pattern),
+		Regex:        regex,
+		StorageClass: strings.TrimSpace(class),
+	}
+
+	var err error
+	if q.MinSize, err = parseSize(minSize); err != nil {
+		return q, err
+	}
+	if q.MaxSize, err = parseSize(maxSize); err != nil {
+		return q, err
+	}
+	if q.ModifiedAfter, err = parseDate(after); err != nil {
+		return q, err
+	}
+	if q.ModifiedBefore, err = parseDate(before); err != nil {
+		return q, err
+	}
+	// A data final é inclusiva: "até 2024-01-31" inclui o dia 31 inteiro
+	if !q.ModifiedBefore.IsZero() {
+		q.ModifiedBefore = q.ModifiedBefore.AddDate(0, 0, 1)
+	}
+
+	q.Tags = parseTagList(tags)
+
+	return q, nil
+}
+
+// parseSize aceita bytes ou valores com unidade (KB, MB, GB, TB; base 1024)
+func parseSize(text string) (int64, error) {
+	text = strings.ToUpper(strings.TrimSpace(text))
+	if text == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	for i, unit := range []string{"KB", "MB", "GB", "TB"} {
+		if strings.HasSuffix(text, unit) {
+			multiplier = int64(1) << (10 * (i + 1))
+			text = strings.TrimSpace(strings.TrimSuffix(text, unit))
+			break
+		}
+	}
+	text = strings.TrimSuffix(text, "B")
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("tamanho inválido: %s", text)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// parseDate aceita datas no formato AAAA-MM-DD (horário local)
+func parseDate(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", text, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("data inválida (use AAAA-MM-DD): %s", text)
+	}
+	return t, nil
+}
@@ -0,0 +1,230 @@
+// ui/selection.go
+package ui
+
+import (
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// selection guarda as linhas marcadas de uma lista. A âncora é o ponto de
+// partida dos intervalos (shift) e o cursor é a linha movida pelo teclado.
+type selection struct {
+	rows   map[int]bool
+	anchor int
+	cursor int
+
+	// OnChanged é chamado após qualquer mudança
+	OnChanged func()
+}
+
+func newSelection() *selection {
+	return &selection{rows: make(map[int]bool), anchor: -1, cursor: -1}
+}
+
+// Click aplica um clique em id: ctrl alterna a linha, shift marca o
+// intervalo desde a âncora e sem modificador marca só a linha
+func (s *selection) Click(id int, mod fyne.KeyModifier) {
+	switch {
+	case mod&fyne.KeyModifierShift != 0 && s.anchor >= 0:
+		s.rows = make(map[int]bool)
+		s.markRange(s.anchor, id)
+	case mod&(fyne.KeyModifierControl|fyne.KeyModifierSuper) != 0:
+		if s.rows[id] {
+			delete(s.rows, id)
+		} else {
+			s.rows[id] = true
+		}
+		s.anchor = id
+	default:
+		s.rows = map[int]bool{id: true}
+		s.anchor = id
+	}
+	s.cursor = id
+	s.changed()
+}
+
+// Move desloca o cursor em delta linhas (de n); com extend, amplia o
+// intervalo a partir da âncora
+func (s *selection) Move(delta, n int, extend bool) {
+	if n == 0 {
+		return
+	}
+	id := s.cursor + delta
+	if s.cursor < 0 {
+		id = 0
+	}
+	id = max(0, min(n-1, id))
+
+	mod := fyne.KeyModifier(0)
+	if extend {
+		mod = fyne.KeyModifierShift
+	}
+	s.Click(id, mod)
+}
+
+// SelectAll marca as n linhas
+func (s *selection) SelectAll(n int) {
+	s.rows = make(map[int]bool, n)
+	s.markRange(0, n-1)
+	s.changed()
+}
+
+// Clear desmarca tudo e esquece âncora e cursor
+func (s *selection) Clear() {
+	s.rows = make(map[int]bool)
+	s.anchor, s.cursor = -1, -1
+	s.changed()
+}
+
+// Has informa se id está marcada
+func (s *selection) Has(id int) bool { return s.rows[id] }
+
+// Cursor é a última linha clicada ou movida (-1 se nenhuma)
+func (s *selection) Cursor() int { return s.cursor }
+
+// IDs retorna as linhas marcadas em ordem
+func (s *selection) IDs() []int {
+	ids := make([]int, 0, len(s.rows))
+	for id := range s.rows {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (s *selection) markRange(from, to int) {
+	if from > to {
+		from, to = to, from
+	}
+	for id := from; id <= to; id++ {
+		s.rows[id] = true
+	}
+}
+
+func (s *selection) changed() {
+	if s.OnChanged != nil {
+		s.OnChanged()
+	}
+}
+
+// listRow é a linha das listas de arquivos: o clique (com ctrl/shift)
+// seleciona e o duplo clique ativa. A altura é a de um Label, como a que
+// listRowAt assume.
+type listRow struct {
+	widget.BaseWidget
+
+	id         widget.ListItemID
+	label      *widget.Label
+	background *canvas.Rectangle
+	onTap      func(id widget.ListItemID, mod fyne.KeyModifier)
+	onActivate func(id widget.ListItemID)
+
+	// Colunas à direita e botão de ação, ocultos até SetDetails/SetAction
+	details *widget.Label
+	action  *widget.Button
+
+	// OnScrolled recebe a rolagem sobre a linha, que não chega mais à
+	// lista; use scrollList para repassá-la
+	OnScrolled func(ev *fyne.ScrollEvent)
+}
+
+var (
+	_ desktop.Mouseable   = (*listRow)(nil)
+	_ fyne.DoubleTappable = (*listRow)(nil)
+	_ fyne.Scrollable     = (*listRow)(nil)
+)
+
+func newListRow(onTap func(widget.ListItemID, fyne.KeyModifier), onActivate func(widget.ListItemID)) *listRow {
+	r := &listRow{
+		id:         -1,
+		label:      widget.NewLabel(""),
+		background: canvas.NewRectangle(theme.Color(theme.ColorNameSelection)),
+		onTap:      onTap,
+		onActivate: onActivate,
+		details:    widget.NewLabel(""),
+		action:     widget.NewButton("", nil),
+	}
+	r.label.Truncation = fyne.TextTruncateEllipsis
+	r.details.TextStyle = fyne.TextStyle{Monospace: true}
+	r.details.Hide()
+	r.action.Importance = widget.LowImportance
+	r.action.Hide()
+	r.background.Hide()
+	r.ExtendBaseWidget(r)
+	return r
+}
+
+// SetAction mostra na linha um botão com icon que chama fn
+func (r *listRow) SetAction(icon fyne.Resource, fn func(id widget.ListItemID)) {
+	r.action.SetIcon(icon)
+	r.action.OnTapped = func() {
+		if r.id >= 0 {
+			fn(r.id)
+		}
+	}
+	r.action.Show()
+}
+
+// SetDetails mostra text nas colunas à direita; action esconde o botão
+// de ação nesta linha quando falso
+func (r *listRow) SetDetails(text string, action bool) {
+	r.details.SetText(text)
+	r.details.Show()
+	if r.action.OnTapped == nil {
+		return
+	}
+	if action {
+		r.action.Show()
+	} else {
+		r.action.Hide()
+	}
+}
+
+// Update mostra text na linha id, destacada se selected
+func (r *listRow) Update(id widget.ListItemID, text string, selected bool) {
+	r.id = id
+	r.label.SetText(text)
+	if selected {
+		r.background.FillColor = theme.Color(theme.ColorNameSelection)
+		r.background.Show()
+	} else {
+		r.background.Hide()
+	}
+	r.background.Refresh()
+}
+
+func (r *listRow) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewStack(r.background,
+		container.NewBorder(nil, nil, nil, container.NewHBox(r.details, r.action), r.label)))
+}
+
+func (r *listRow) MouseDown(ev *desktop.MouseEvent) {
+	if ev.Button == desktop.MouseButtonPrimary && r.id >= 0 && r.onTap != nil {
+		r.onTap(r.id, ev.Modifier)
+	}
+}
+
+func (r *listRow) MouseUp(*desktop.MouseEvent) {}
+
+func (r *listRow) DoubleTapped(*fyne.PointEvent) {
+	if r.id >= 0 && r.onActivate != nil {
+		r.onActivate(r.id)
+	}
+}
+
+func (r *listRow) Scrolled(ev *fyne.ScrollEvent) {
+	if r.OnScrolled != nil {
+		r.OnScrolled(ev)
+	}
+}
+
+// scrollList aplica a rolagem ev a list, como a barra da própria lista faria
+func scrollList(list *widget.List, ev *fyne.ScrollEvent) {
+	list.ScrollToOffset(list.GetScrollOffset() - ev.Scrolled.DY)
+}
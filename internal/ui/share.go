@@ -0,0 +1,64 @@
+// ui/share.go
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Validades oferecidas para links de compartilhamento
+var shareExpirations = map[string]time.Duration{
+	"1 hora":   time.Hour,
+	"24 horas": 24 * time.Hour,
+	"7 dias":   7 * 24 * time.Hour,
+}
+
+// showShareDialog gera um link temporário para bucket/key e permite copiá-lo
+func showShareDialog(w fyne.Window, client storageBackend, bucket, key string) {
+	p, ok := client.(presigner)
+	if !ok {
+		dialog.ShowInformation("Compartilhar",
+			"Este backend não suporta links de compartilhamento", w)
+		return
+	}
+
+	urlEntry := widget.NewMultiLineEntry()
+	urlEntry.Wrapping = fyne.TextWrapBreak
+	urlEntry.SetMinRowsVisible(4)
+
+	generate := func(label string) {
+		url, err := p.PresignGetObject(context.Background(), bucket, key, shareExpirations[label])
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		urlEntry.SetText(url)
+	}
+
+	expiresSelect := widget.NewSelect([]string{"1 hora", "24 horas", "7 dias"}, generate)
+
+	copyBtn := widget.NewButton("Copiar", func() {
+		fyne.CurrentApp().Clipboard().SetContent(urlEntry.Text)
+	})
+
+	content := container.NewBorder(
+		container.NewVBox(
+			widget.NewLabel(fmt.Sprintf("%s/%s", bucket, key)),
+			widget.NewForm(widget.NewFormItem("Validade", expiresSelect)),
+		),
+		copyBtn, nil, nil,
+		urlEntry,
+	)
+
+	d := dialog.NewCustom("Compartilhar", "Fechar", content, w)
+	d.Resize(fyne.NewSize(560, 300))
+	d.Show()
+
+	expiresSelect.SetSelected("24 horas")
+}
@@ -0,0 +1,160 @@
+// ui/size.go
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// Quantos maiores objetos exibir no cálculo de tamanho
+	largestObjectsLimit = 10
+	// Quantas subpastas exibir (as maiores)
+	subfolderLimit = 20
+	// Intervalo entre atualizações parciais na tela
+	sizeRefreshInterval = 300 * time.Millisecond
+)
+
+// showSizeDialog calcula em segundo plano o tamanho exato de bucket/prefix,
+// exibindo resultados parciais enquanto a listagem avança
+func showSizeDialog(w fyne.Window, client storageBackend, bucket, prefix string) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	summary := widget.NewLabel("Calculando...")
+	details := widget.NewLabel("")
+	details.TextStyle = fyne.TextStyle{Monospace: true}
+	progress := widget.NewProgressBarInfinite()
+
+	content := container.NewBorder(
+		container.NewVBox(summary, progress),
+		nil, nil, nil,
+		container.NewVScroll(details),
+	)
+
+	d := dialog.NewCustom(
+		fmt.Sprintf("Tamanho de %s/%s", bucket, prefix),
+		"Cancelar", content, w)
+	d.SetOnClosed(cancel)
+	d.Resize(fyne.NewSize(640, 480))
+	d.Show()
+
+	show := func(stats models.PrefixStats, done bool) {
+		state := "calculando..."
+		if done {
+			state = "concluído"
+		}
+		summary.SetText(fmt.Sprintf("%s em %d objeto(s) (%s)",
+			formatBytes(stats.Total.Bytes), stats.Total.Count, state))
+		details.SetText(formatPrefixStats(stats))
+	}
+
+	go func() {
+		stats := models.NewPrefixStats(bucket, prefix, largestObjectsLimit)
+		var lastUpdate time.Time
+
+		err := client.WalkObjects(ctx, bucket, prefix, func(obj models.Object) error {
+			stats.Add(obj)
+			if time.Since(lastUpdate) < sizeRefreshInterval {
+				return nil
+			}
+			lastUpdate = time.Now()
+			snapshot := stats.Snapshot()
+
+			runOnUIThread(func() {
+				show(snapshot, false)
+			})
+			return nil
+		})
+
+		snapshot := stats.Snapshot()
+
+		runOnUIThread(func() {
+			progress.Stop()
+			progress.Hide()
+			d.SetDismissText("Fechar")
+
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			show(snapshot, err == nil)
+			if err != nil {
+				dialog.ShowError(err, w)
+			}
+		})
+	}()
+}
+
+// formatPrefixStats monta o detalhamento por classe, subpasta e maiores objetos
+func formatPrefixStats(stats models.PrefixStats) string {
+	var b strings.Builder
+
+	b.WriteString("Por classe de armazenamento:\n")
+	for _, e := range sortUsage(stats.ByStorageClass) {
+		fmt.Fprintf(&b, "  %-22s %12s  %10d obj\n", e.name, formatBytes(e.Bytes), e.Count)
+	}
+
+	b.WriteString("\nPor subpasta:\n")
+	for i, e := range sortUsage(stats.BySubfolder) {
+		if i == subfolderLimit {
+			fmt.Fprintf(&b, "  ... e mais %d subpasta(s)\n", len(stats.BySubfolder)-subfolderLimit)
+			break
+		}
+		name := e.name
+		if name == "" {
+			name = "(arquivos nesta pasta)"
+		}
+		fmt.Fprintf(&b, "  %-40s %12s  %10d obj\n", name, formatBytes(e.Bytes), e.Count)
+	}
+
+	b.WriteString("\nMaiores objetos:\n")
+	for _, obj := range stats.Largest {
+		fmt.Fprintf(&b, "  %12s  %s\n", formatBytes(obj.Size), obj.Key)
+	}
+
+	return b.String()
+}
+
+type namedUsage struct {
+	name string
+	models.Usage
+}
+
+// sortUsage ordena as entradas do maior para o menor em bytes
+func sortUsage(m map[string]models.Usage) []namedUsage {
+	out := make([]namedUsage, 0, len(m))
+	for name, u := range m {
+		out = append(out, namedUsage{name: name, Usage: u})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Bytes != out[j].Bytes {
+			return out[i].Bytes > out[j].Bytes
+		}
+		return out[i].name < out[j].name
+	})
+	return out
+}
+
+// formatBytes formata um tamanho em unidades binárias (KiB, MiB, ...)
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
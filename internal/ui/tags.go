@@ -0,0 +1,180 @@
+// ui/tags.go
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// parseTagList aceita "chave=valor" separados por vírgula ou linha; sem
+// "=", a chave fica com valor vazio (ex: "a=1, b")
+func parseTagList(text string) map[string]string {
+	tags := make(map[string]string)
+	for _, part := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '\n' }) {
+		k, v, _ := strings.Cut(part, "=")
+		if k = strings.TrimSpace(k); k != "" {
+			tags[k] = strings.TrimSpace(v)
+		}
+	}
+	return tags
+}
+
+// showTagEditor edita as tags de um objeto. onSaved recebe as tags gravadas.
+func showTagEditor(w fyne.Window, client storageBackend, bucket, key string, onSaved func(map[string]string)) {
+	tr, canRead := client.(tagReader)
+	tw, canWrite := client.(tagWriter)
+	if !canRead || !canWrite {
+		dialog.ShowInformation("Tags", "Este backend não suporta tags", w)
+		return
+	}
+
+	editor := widget.NewMultiLineEntry()
+	editor.SetPlaceHolder("centro-de-custo=marketing\nretencao=5-anos")
+	editor.SetMinRowsVisible(6)
+	editor.Disable()
+
+	go func() {
+		tags, err := tr.GetObjectTags(context.Background(), bucket, key)
+		runOnUIThread(func() {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			editor.SetText(models.FormatTags(tags, "\n"))
+			editor.Enable()
+		})
+	}()
+
+	content := container.NewBorder(
+		widget.NewLabel(fmt.Sprintf("%s\nUma tag por linha (máximo %d)", key, models.MaxObjectTags)),
+		nil, nil, nil, editor,
+	)
+
+	d := dialog.NewCustomConfirm("Tags do objeto", "Salvar", "Cancelar", content, func(save bool) {
+		if !save {
+			return
+		}
+		tags := parseTagList(editor.Text)
+		go func() {
+			err := tw.PutObjectTags(context.Background(), bucket, key, tags)
+			runOnUIThread(func() {
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				if onSaved != nil {
+					onSaved(tags)
+				}
+			})
+		}()
+	}, w)
+	d.Resize(fyne.NewSize(480, 320))
+	d.Show()
+}
+
+// showBulkTagWindow aplica uma edição de tags a keys ou, se keys for vazio,
+// a todos os objetos sob prefix
+func showBulkTagWindow(w fyne.Window, client storageBackend, bucket, prefix string, keys []string) {
+	tr, canRead := client.(tagReader)
+	tw, canWrite := client.(tagWriter)
+	if !canRead || !canWrite {
+		dialog.ShowInformation("Tags", "Este backend não suporta tags", w)
+		return
+	}
+
+	setEntry := widget.NewEntry()
+	setEntry.SetPlaceHolder("centro-de-custo=marketing, retencao=5-anos")
+	removeEntry := widget.NewEntry()
+	removeEntry.SetPlaceHolder("chaves separadas por vírgula")
+	replaceCheck := widget.NewCheck("Substituir todas as tags atuais", nil)
+
+	form := widget.NewForm(
+		widget.NewFormItem("Definir", setEntry),
+		widget.NewFormItem("Remover", removeEntry),
+		widget.NewFormItem("", replaceCheck),
+	)
+
+	showBulkWindow(w, client, bucket, prefix, keys, "Tags em massa", form, func(bw fyne.Window, start func(bulkAction)) {
+		edit := models.TagEdit{
+			Set:     parseTagList(setEntry.Text),
+			Replace: replaceCheck.Checked,
+		}
+		for _, k := range strings.Split(removeEntry.Text, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				edit.Remove = append(edit.Remove, k)
+			}
+		}
+		if len(edit.Set) == 0 && len(edit.Remove) == 0 && !edit.Replace {
+			dialog.ShowInformation("Tags em massa", "Informe tags para definir ou remover", bw)
+			return
+		}
+		if err := models.ValidateTags(edit.Set); err != nil {
+			dialog.ShowError(err, bw)
+			return
+		}
+
+		start(func(ctx context.Context, key string) error {
+			return applyTagEdit(ctx, tr, tw, bucket, key, edit)
+		})
+	})
+}
+
+// applyTagEdit lê, altera e grava as tags de um objeto
+func applyTagEdit(ctx context.Context, tr tagReader, tw tagWriter, bucket, key string, edit models.TagEdit) error {
+	var current map[string]string
+	if !edit.Replace {
+		var err error
+		if current, err = tr.GetObjectTags(ctx, bucket, key); err != nil {
+			return err
+		}
+	}
+	return tw.PutObjectTags(ctx, bucket, key, edit.Apply(current))
+}
+
+// filterByTags mantém pastas e os arquivos cujas tags satisfazem query
+func filterByTags(ctx context.Context, tr tagReader, bucket string, items []models.Item, query models.SearchQuery) []models.Item {
+	keep := make([]bool, len(items))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < bulkWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				tags, err := tr.GetObjectTags(ctx, bucket, items[idx].Prefix)
+				keep[idx] = err == nil && query.MatchTags(tags)
+			}
+		}()
+	}
+
+	for i, item := range items {
+		if item.Type != models.File {
+			keep[i] = true
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	out := items[:0:0]
+	for i, item := range items {
+		if keep[i] {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,147 @@
+// ui/uploadrules.go
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"s3nd-files/internal/headers"
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showUploadRulesDialog edita as regras de cabeçalhos dos envios e chama
+// onSave com as regras novas
+func showUploadRulesDialog(w fyne.Window, rules []headers.Rule, onSave func([]headers.Rule)) {
+	rules = append([]headers.Rule(nil), rules...)
+	selected := -1
+
+	patternEntry := widget.NewEntry()
+	patternEntry.SetPlaceHolder("*.html")
+	typeEntry := widget.NewEntry()
+	typeEntry.SetPlaceHolder("detectado pela extensão ou conteúdo")
+	cacheEntry := widget.NewEntry()
+	cacheEntry.SetPlaceHolder("no-cache")
+	encodingEntry := widget.NewEntry()
+	encodingEntry.SetPlaceHolder("gzip")
+	dispositionEntry := widget.NewEntry()
+	dispositionEntry.SetPlaceHolder("attachment")
+	metadataEntry := widget.NewMultiLineEntry()
+	metadataEntry.SetPlaceHolder("origem=site\nversao=2")
+	metadataEntry.SetMinRowsVisible(3)
+	form := widget.NewForm(
+		widget.NewFormItem("Padrão", patternEntry),
+		widget.NewFormItem("Content-Type", typeEntry),
+		widget.NewFormItem("Cache-Control", cacheEntry),
+		widget.NewFormItem("Content-Encoding", encodingEntry),
+		widget.NewFormItem("Content-Disposition", dispositionEntry),
+		widget.NewFormItem("Metadados", metadataEntry),
+	)
+	form.Hide()
+
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(rules) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(rules[id].Pattern)
+		},
+	)
+
+	// Os campos gravam direto na regra selecionada
+	loading := false
+	store := func(string) {
+		if loading || selected < 0 {
+			return
+		}
+		rules[selected] = headers.Rule{
+			Pattern: patternEntry.Text,
+			Headers: models.ObjectHeaders{
+				ContentType:        typeEntry.Text,
+				CacheControl:       cacheEntry.Text,
+				ContentEncoding:    encodingEntry.Text,
+				ContentDisposition: dispositionEntry.Text,
+				Metadata:           parseMetadata(metadataEntry.Text),
+			},
+		}
+		list.RefreshItem(selected)
+	}
+	for _, e := range []*widget.Entry{patternEntry, typeEntry, cacheEntry, encodingEntry, dispositionEntry, metadataEntry} {
+		e.OnChanged = store
+	}
+
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		h := rules[id].Headers
+		loading = true
+		patternEntry.SetText(rules[id].Pattern)
+		typeEntry.SetText(h.ContentType)
+		cacheEntry.SetText(h.CacheControl)
+		encodingEntry.SetText(h.ContentEncoding)
+		dispositionEntry.SetText(h.ContentDisposition)
+		metadataEntry.SetText(models.FormatTags(h.Metadata, "\n"))
+		loading = false
+		form.Show()
+	}
+
+	addBtn := widget.NewButton("Adicionar", func() {
+		rules = append(rules, headers.Rule{Pattern: "*"})
+		list.Refresh()
+		list.Select(len(rules) - 1)
+	})
+	removeBtn := widget.NewButton("Remover", func() {
+		if selected < 0 {
+			return
+		}
+		rules = append(rules[:selected], rules[selected+1:]...)
+		selected = -1
+		list.UnselectAll()
+		list.Refresh()
+		form.Hide()
+	})
+
+	help := widget.NewLabel("Padrões como no .gitignore (\"*.html\", \"assets/**\"). Campos vazios\n" +
+		"mantêm o detectado; as regras de baixo prevalecem sobre as de cima.")
+	help.Importance = widget.LowImportance
+
+	split := container.NewHSplit(
+		container.NewBorder(nil, container.NewHBox(addBtn, removeBtn), nil, nil, list),
+		container.NewVScroll(form),
+	)
+	split.Offset = 0.3
+
+	d := dialog.NewCustomConfirm("Regras de cabeçalhos", "Aplicar", "Cancelar",
+		container.NewBorder(nil, help, nil, nil, split), func(ok bool) {
+			if !ok {
+				return
+			}
+			if _, err := headers.NewResolver(rules); err != nil {
+				dialog.ShowError(fmt.Errorf("regras não salvas: %w", err), w)
+				return
+			}
+			onSave(rules)
+		}, w)
+	d.Resize(fyne.NewSize(720, 480))
+	d.Show()
+}
+
+// parseMetadata lê um metadado chave=valor por linha. Diferente das tags,
+// o valor pode ter vírgulas (ex: "autores=Ana, Bruno").
+func parseMetadata(text string) map[string]string {
+	meta := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(line, "=")
+		if k = strings.TrimSpace(k); k != "" {
+			meta[k] = strings.TrimSpace(v)
+		}
+	}
+	return meta
+}
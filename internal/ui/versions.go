@@ -0,0 +1,210 @@
+// ui/versions.go
+package ui
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"s3nd-files/internal/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showVersionsDialog exibe o histórico de versões de bucket/key com ações
+// para baixar, restaurar ou excluir permanentemente cada versão
+func showVersionsDialog(w fyne.Window, client storageBackend, bucket, key string) {
+	v, ok := client.(versioner)
+	if !ok {
+		dialog.ShowInformation("Versões", "Este backend não suporta versionamento", w)
+		return
+	}
+
+	var (
+		versions []models.ObjectVersion
+		selected = -1
+	)
+
+	status := widget.NewLabel("Carregando versões...")
+	list := widget.NewList(
+		func() int { return len(versions) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id < 0 || id >= len(versions) {
+				return
+			}
+			obj.(*widget.Label).SetText(formatVersion(versions[id]))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(widget.ListItemID) { selected = -1 }
+
+	reload := func() {
+		status.SetText("Carregando versões...")
+		go func() {
+			out, err := v.ListObjectVersions(context.Background(), bucket, key)
+			runOnUIThread(func() {
+				if err != nil {
+					status.SetText("Falha ao carregar versões")
+					dialog.ShowError(err, w)
+					return
+				}
+				versions = out
+				selected = -1
+				list.UnselectAll()
+				list.Refresh()
+				status.SetText(fmt.Sprintf("%d versão(ões)", len(versions)))
+			})
+		}()
+	}
+
+	selectedVersion := func() (models.ObjectVersion, bool) {
+		if selected < 0 || selected >= len(versions) {
+			dialog.ShowInformation("Nenhuma versão", "Selecione uma versão primeiro", w)
+			return models.ObjectVersion{}, false
+		}
+		return versions[selected], true
+	}
+
+	downloadBtn := widget.NewButton("Baixar versão", func() {
+		ver, ok := selectedVersion()
+		if !ok {
+			return
+		}
+		if ver.IsDeleteMarker {
+			dialog.ShowInformation("Baixar versão", "Marcadores de exclusão não têm conteúdo", w)
+			return
+		}
+
+		saveDialog := dialog.NewFileSave(func(wc fyne.URIWriteCloser, err error) {
+			if err != nil || wc == nil {
+				return
+			}
+			dest := wc.URI().Path()
+			wc.Close()
+
+			var start func()
+			start = func() {
+				go func() {
+					err := v.DownloadFileVersion(context.Background(), bucket, key, ver.VersionID, dest)
+					runOnUIThread(func() {
+						if err != nil {
+							if !handleKeyError(w, client, err, start) {
+								dialog.ShowError(err, w)
+							}
+							return
+						}
+						dialog.ShowInformation("Download concluído",
+							fmt.Sprintf("Versão salva em:\n%s", dest), w)
+					})
+				}()
+			}
+			start()
+		}, w)
+		saveDialog.SetFileName(path.Base(key))
+		saveDialog.Show()
+	})
+
+	restoreBtn := widget.NewButton("Restaurar como atual", func() {
+		ver, ok := selectedVersion()
+		if !ok {
+			return
+		}
+		if ver.IsDeleteMarker || ver.IsLatest {
+			dialog.ShowInformation("Restaurar versão",
+				"Selecione uma versão anterior (não um marcador de exclusão)", w)
+			return
+		}
+
+		dialog.ShowConfirm("Restaurar versão",
+			fmt.Sprintf("Copiar a versão de %s sobre %s?\n\nA versão atual continua no histórico.",
+				ver.LastModified.Local().Format("2006-01-02 15:04:05"), key),
+			func(confirm bool) {
+				if !confirm {
+					return
+				}
+				var restore func()
+				restore = func() {
+					progress := dialog.NewProgressInfinite("Restaurar versão", "Copiando versão...", w)
+					progress.Show()
+					go func() {
+						err := v.RestoreVersion(context.Background(), bucket, key, ver.VersionID)
+						runOnUIThread(func() {
+							progress.Hide()
+							if err != nil {
+								if !handleKeyError(w, client, err, restore) {
+									dialog.ShowError(err, w)
+								}
+								return
+							}
+							reload()
+						})
+					}()
+				}
+				restore()
+			}, w)
+	})
+
+	deleteBtn := widget.NewButton("Excluir permanentemente", func() {
+		ver, ok := selectedVersion()
+		if !ok {
+			return
+		}
+
+		what := "a versão " + ver.VersionID
+		if ver.IsDeleteMarker {
+			what = "o marcador de exclusão " + ver.VersionID
+		}
+		dialog.ShowConfirm("Excluir permanentemente",
+			fmt.Sprintf("Excluir %s de %s?\n\nEsta ação não pode ser desfeita.", what, key),
+			func(confirm bool) {
+				if !confirm {
+					return
+				}
+				progress := dialog.NewProgressInfinite("Excluir permanentemente", "Excluindo versão...", w)
+				progress.Show()
+				go func() {
+					err := v.DeleteVersion(context.Background(), bucket, key, ver.VersionID)
+					runOnUIThread(func() {
+						progress.Hide()
+						if err != nil {
+							dialog.ShowError(err, w)
+							return
+						}
+						reload()
+					})
+				}()
+			}, w)
+	})
+	deleteBtn.Importance = widget.DangerImportance
+
+	content := container.NewBorder(
+		widget.NewLabel(fmt.Sprintf("%s/%s", bucket, key)),
+		container.NewVBox(status, container.NewHBox(downloadBtn, restoreBtn, deleteBtn)),
+		nil, nil,
+		list,
+	)
+
+	d := dialog.NewCustom("Versões", "Fechar", content, w)
+	d.Resize(fyne.NewSize(700, 450))
+	d.Show()
+
+	reload()
+}
+
+// formatVersion monta a linha de uma versão na lista
+func formatVersion(v models.ObjectVersion) string {
+	text := v.LastModified.Local().Format("2006-01-02 15:04:05")
+	if v.IsDeleteMarker {
+		text += "  🗑 marcador de exclusão"
+	} else {
+		text += "  " + formatBytes(v.Size)
+	}
+	if v.IsLatest {
+		text += "  (atual)"
+	}
+	return text + "  " + v.VersionID
+}
@@ -3,19 +3,27 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 
+	"s3nd-files/internal/cache"
+	"s3nd-files/internal/filter"
+	"s3nd-files/internal/headers"
 	"s3nd-files/internal/services/aws"
+	"s3nd-files/internal/services/local"
+	"s3nd-files/internal/settings"
 	"s3nd-files/internal/models"
+	"s3nd-files/internal/naming"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
@@ -100,6 +108,9 @@ func showConnectionDialog(w fyne.Window, onConnect func(aws.Config)) {
 		}
 	})
 	presetSelect.SetSelected("Customizado")
+
+	encryptionItems, readEncryption := encryptionFormItems(w, models.Encryption{})
+	masterKeyItems, readMasterKey := masterKeyFormItems(w, true)
 	
 	// Formulário
 	form := &widget.Form{
@@ -119,6 +130,16 @@ func showConnectionDialog(w fyne.Window, onConnect func(aws.Config)) {
 				dialog.ShowError(fmt.Errorf("credenciais são obrigatórias"), w)
 				return
 			}
+			encryption, err := readEncryption()
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			masterKey, err := readMasterKey()
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
 			
 			cfg := aws.Config{
 				Endpoint:        endpointEntry.Text,
@@ -128,6 +149,8 @@ func showConnectionDialog(w fyne.Window, onConnect func(aws.Config)) {
 				UseSSL:          useSSLCheck.Checked,
 				ForcePathStyle:  pathStyleCheck.Checked,
 				DisableSSL:      disableSSLCheck.Checked,
+				Encryption:      encryption,
+				MasterKey:       masterKey,
 			}
 			
 			// Validar endpoint
@@ -177,6 +200,9 @@ func showConnectionDialog(w fyne.Window, onConnect func(aws.Config)) {
 	form.Append("", useSSLCheck)
 	form.Append("", pathStyleCheck)
 	form.Append("", disableSSLCheck)
+	for _, item := range append(encryptionItems, masterKeyItems...) {
+		form.AppendItem(item)
+	}
 	
 	// Diálogo
 	dialog.ShowCustom("Configurar Conexão S3", "Fechar", form, w)
@@ -218,43 +244,205 @@ func showAdvancedSettings(w fyne.Window) {
 	dialog.ShowCustom("Configurações Avançadas", "Fechar", form, w)
 }
 
+const (
+	// Tamanho de cada página pedida ao backend
+	listPageSize int32 = 1000
+	// Páginas buscadas automaticamente antes de exibir "carregar mais"
+	listAutoPages = 5
+	// Prefixo sentinela da linha "carregar mais" em s3Items
+	loadMorePrefix = "\x00LOAD_MORE"
+)
+
+// runOnUIThread agenda f na thread da interface. Toda alteração de widgets
+// ou do estado da janela feita a partir de goroutines passa por aqui.
+func runOnUIThread(f func()) {
+	fyne.Do(f)
+}
+
 func Run() {
 	a := app.New()
 	w := a.NewWindow("S3 Uploader")
 	w.Resize(fyne.NewSize(900, 500))
 
+	prefs, err := settings.Open()
+	if err != nil {
+		fmt.Printf("⚠️ Preferências não carregadas, usando apenas nesta sessão: %v\n", err)
+		prefs = settings.Memory()
+	}
 
-	runOnUIThread := func(f func()) {
-		// Executa diretamente (funciona para muitas operações do Fyne)
-		// Se houver problemas, troque por fyne.CurrentApp().Run()
-		f()
+	// Cópias de objetos abertos em aplicativos externos
+	openCache, err := cache.Open()
+	if err != nil {
+		fmt.Printf("⚠️ Cache indisponível, \"Abrir com\" desativado: %v\n", err)
+	} else {
+		go openCache.Prune()
 	}
+
 	// =====================
 	// Arquivos locais
 	// =====================
-	fileSet := make(map[string]struct{})
-	var files []string
+	// Arquivos agrupados pela pasta de origem, com tamanho e data
+	localView := newLocalFiles()
+	folderRules := prefs.FolderFilter()
+
+	showExcludedCheck := widget.NewCheck("Mostrar excluídos", nil)
+	localCount := widget.NewLabel("")
+
+	// Painel que recebe os atalhos de teclado (o último clicado)
+	activeS3 := false
+
+	var localList *widget.List
+	localSel := newSelection()
+
+	// refreshList redesenha a lista após mudar a estrutura (as linhas mudam
+	// de posição, então a seleção é desfeita)
+	refreshList := func() {
+		localSel.Clear()
+		localList.Refresh()
+	}
+
+	// Resumo com o total e o que está selecionado
+	updateLocalCount := func() {
+		count, size, excluded := localView.Totals()
+		text := fmt.Sprintf("%d arquivo(s) · %s", count, formatBytes(size))
+		var selCount int
+		var selSize int64
+		for _, id := range localSel.IDs() {
+			if f, ok := localView.File(id); ok {
+				selCount++
+				selSize += f.Size
+			}
+		}
+		if selCount > 0 {
+			text += fmt.Sprintf(" · %d selecionado(s), %s", selCount, formatBytes(selSize))
+		}
+		if excluded > 0 {
+			text += fmt.Sprintf(" · %d excluído(s) pelos filtros", excluded)
+		}
+		localCount.SetText(text)
+	}
+
+	// Varrer pastas com os filtros atuais, em segundo plano
+	scanRoots := func(roots []string) {
+		if len(roots) == 0 {
+			refreshList()
+			return
+		}
+		rules, removed := folderRules, localView.Removed()
+		progress := dialog.NewProgressInfinite("Arquivos locais", "Lendo pastas...", w)
+		progress.Show()
+		go func() {
+			groups := make([]*localGroup, 0, len(roots))
+			var errs []string
+			for _, root := range roots {
+				g, err := scanRoot(root, rules, removed)
+				if err != nil {
+					errs = append(errs, err.Error())
+					continue
+				}
+				groups = append(groups, g)
+			}
+			runOnUIThread(func() {
+				progress.Hide()
+				for _, g := range groups {
+					localView.SetGroup(g)
+				}
+				refreshList()
+				if len(errs) > 0 {
+					dialog.ShowError(errors.New(strings.Join(errs, "\n")), w)
+				}
+			})
+		}()
+	}
+	rescan := func() { scanRoots(localView.Roots()) }
 
-	localList := widget.NewList(
+	// Clique seleciona (ctrl/shift para vários) e, no cabeçalho de uma
+	// pasta, recolhe ou expande; duplo clique abre o arquivo no aplicativo
+	// padrão; o botão da linha tira o arquivo ou a pasta da lista
+	localList = widget.NewList(
 		func() int {
-			return len(files)
+			return localView.Len()
 		},
 		func() fyne.CanvasObject {
-			return widget.NewLabel("")
+			row := newListRow(func(id widget.ListItemID, mod fyne.KeyModifier) {
+				activeS3 = false
+				w.Canvas().Unfocus()
+				r, ok := localView.Row(id)
+				switch {
+				case !ok:
+				case r.Kind == rowGroup:
+					localView.Toggle(r.Group)
+					refreshList()
+				case r.Kind == rowFile:
+					localSel.Click(id, mod)
+				}
+			}, func(id widget.ListItemID) {
+				if f, ok := localView.File(id); ok {
+					if err := launchDefaultApp(f.Path); err != nil {
+						dialog.ShowError(err, w)
+					}
+				}
+			})
+			row.OnScrolled = func(ev *fyne.ScrollEvent) { scrollList(localList, ev) }
+			row.SetAction(theme.CancelIcon(), func(id widget.ListItemID) {
+				r, ok := localView.Row(id)
+				switch {
+				case !ok:
+				case r.Kind == rowGroup:
+					localView.RemoveGroup(r.Group)
+					refreshList()
+				case r.Kind == rowFile:
+					localView.Remove([]string{r.Group.Files[r.Index].Path})
+					refreshList()
+				}
+			})
+			return row
 		},
 		func(id widget.ListItemID, obj fyne.CanvasObject) {
-			obj.(*widget.Label).SetText(files[id])
+			row := obj.(*listRow)
+			name, details := localView.Text(id)
+			r, _ := localView.Row(id)
+			row.Update(id, name, r.Kind == rowFile && localSel.Has(id))
+			row.SetDetails(details, r.Kind != rowExcluded)
 		},
 	)
+	localSel.OnChanged = func() {
+		localList.Refresh()
+		updateLocalCount()
+	}
+	showExcludedCheck.OnChanged = func(show bool) {
+		localView.SetShowExcluded(show)
+		refreshList()
+	}
 
-	refreshList := func() {
-		files = files[:0]
-		for path := range fileSet {
-			files = append(files, path)
+	// Cabeçalho das colunas: clicar ordena, clicar de novo inverte
+	var sortBtns []*widget.Button
+	sortLabels := []string{"Nome", "Tamanho", "Modificado"}
+	updateSortBtns := func() {
+		for i, btn := range sortBtns {
+			text := sortLabels[i]
+			switch {
+			case localSort(i) != localView.sortBy:
+			case localView.descending:
+				text += " ▼"
+			default:
+				text += " ▲"
+			}
+			btn.SetText(text)
 		}
-		sort.Strings(files)
-		localList.Refresh()
 	}
+	for i := range sortLabels {
+		by := localSort(i)
+		btn := widget.NewButton(sortLabels[i], func() {
+			localView.SetSort(by)
+			updateSortBtns()
+			refreshList()
+		})
+		btn.Importance = widget.LowImportance
+		sortBtns = append(sortBtns, btn)
+	}
+	updateSortBtns()
+	localColumns := container.NewBorder(nil, nil, nil, container.NewHBox(sortBtns[1], sortBtns[2]), container.NewHBox(sortBtns[0]))
 
 	selectFolderBtn := widget.NewButton("Selecionar pasta", func() {
 		dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
@@ -262,63 +450,48 @@ func Run() {
 				return
 			}
 
-			root := uri.Path()
-
-			filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
-				if err != nil {
-					return nil
-				}
-				if !d.IsDir() {
-					fileSet[path] = struct{}{}
-				}
-				return nil
-			})
-
-			refreshList()
+			scanRoots([]string{uri.Path()})
 		}, w)
 	})
 
+	filterBtn := widget.NewButton("🔎 Filtros", func() {
+		showFolderFilterDialog(w, folderRules, func(rules filter.Rules) {
+			folderRules = rules
+			if err := prefs.SetFolderFilter(rules); err != nil {
+				dialog.ShowError(err, w)
+			}
+			rescan()
+		})
+	})
+
 	selectFileBtn := widget.NewButton("Selecionar arquivo", func() {
 		dialog.ShowFileOpen(func(r fyne.URIReadCloser, err error) {
 			if err != nil || r == nil {
 				return
 			}
-			fileSet[r.URI().Path()] = struct{}{}
+			r.Close()
+			if err := localView.AddFiles([]string{r.URI().Path()}); err != nil {
+				dialog.ShowError(err, w)
+			}
 			refreshList()
 		}, w)
 	})
 
 	clearBtn := widget.NewButton("Limpar seleção", func() {
-		fileSet = make(map[string]struct{})
-		files = files[:0]
-		localList.Refresh()
+		localView.Clear()
+		refreshList()
 	})
 
-	localHeader := widget.NewLabelWithStyle(
-		"Arquivos locais",
-		fyne.TextAlignLeading,
-		fyne.TextStyle{Bold: true},
-	)
-
-	localPanel := container.NewBorder(
-		container.NewVBox(
-			localHeader,
-			container.NewHBox(selectFolderBtn, selectFileBtn, clearBtn),
-		),
-		nil,
-		nil,
-		nil,
-		localList,
-	)
-
 	// =====================
 	// S3 - variáveis
 	// =====================
 	var (
-		s3Client    *aws.Client
+		s3Client    storageBackend
 		// esse []Item deveria ser de outro pacote, mas depois eu mexo nele (types.go)
 		s3Items     []models.Item
 		s3Connected bool
+		// Perfil da conexão atual, para as preferências por conexão
+		s3Profile string
 	)
 
 	// Variáveis para navegação
@@ -328,9 +501,33 @@ func Run() {
 	s3Header := widget.NewLabelWithStyle("Arquivos na S3", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
 	s3Status := widget.NewLabel("Não conectado")
 
-	s3List := widget.NewList(
+	// Definida junto com a listagem incremental, mais abaixo
+	var (
+		loadMore      func()
+		loadIfVisible func()
+		listCtx       context.Context
+	)
+
+	// Clique e ativação (duplo clique ou Enter) das linhas da S3, definidos
+	// junto com a navegação, mais abaixo
+	var (
+		onS3Tap    func(id widget.ListItemID, mod fyne.KeyModifier)
+		activateS3 func(id widget.ListItemID)
+	)
+	s3Sel := newSelection()
+
+	var s3List *widget.List
+	s3List = widget.NewList(
 		func() int { return len(s3Items) },
-		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func() fyne.CanvasObject {
+			row := newListRow(func(id widget.ListItemID, mod fyne.KeyModifier) { onS3Tap(id, mod) },
+				func(id widget.ListItemID) { activateS3(id) })
+			row.OnScrolled = func(ev *fyne.ScrollEvent) {
+				scrollList(s3List, ev)
+				loadIfVisible()
+			}
+			return row
+		},
 		func(id widget.ListItemID, obj fyne.CanvasObject) {
 			if id < 0 || id >= len(s3Items) {
 				return
@@ -352,10 +549,49 @@ func Run() {
 			// 	icon = "📁 "
 			// }
 
-			obj.(*widget.Label).SetText(icon + item.Name)
+			obj.(*listRow).Update(id, icon+item.Name+storageClassSuffix(item), s3Sel.Has(id))
 		},
 	)
 
+	// Itens selecionados na lista S3, sem ".." e a linha "carregar mais"
+	selectedS3Items := func() []models.Item {
+		var items []models.Item
+		for _, id := range s3Sel.IDs() {
+			if id >= len(s3Items) {
+				continue
+			}
+			item := s3Items[id]
+			if item.Name == ".." || item.Prefix == loadMorePrefix || item.Type == models.Bucket {
+				continue
+			}
+			items = append(items, item)
+		}
+		return items
+	}
+
+	// withSelectedKeys chama fn com as chaves dos itens selecionados,
+	// incluindo o conteúdo das pastas, ou com nil se nada foi selecionado
+	withSelectedKeys := func(fn func(keys []string)) {
+		items := selectedS3Items()
+		if len(items) == 0 {
+			fn(nil)
+			return
+		}
+		client, bucket, prefix := s3Client, currentBucket, currentPrefix
+		var keys []string
+		runFileOperation(w, "Selecionando objetos", func(ctx context.Context, step func(string)) error {
+			return walkItems(ctx, client, bucket, prefix, items, func(key, _ string) error {
+				keys = append(keys, key)
+				step(fmt.Sprintf("%d objeto(s) encontrados...", len(keys)))
+				return nil
+			})
+		}, func(err error) {
+			if err == nil && len(keys) > 0 {
+				fn(keys)
+			}
+		})
+	}
+
 	// Função auxiliar para obter prefixo pai
 	getParentPrefix := func(prefix string) string {
 		if prefix == "" {
@@ -368,135 +604,352 @@ func Run() {
 		return strings.Join(parts[:len(parts)-1], "/") + "/"
 	}
 
-	// Adicione esta função APÓS getParentPrefix e ANTES de "Container inicial da S3"
-// Funções auxiliares
-	// loadOnlyFolders := func(bucket, prefix string, count int) {
-	// 	items, _, err := s3Client.ListObjectsPaginated(context.Background(), bucket, prefix, 100)
-	// 	if err != nil {
-	// 		runOnUIThread(func() {
-	// 			dialog.ShowError(fmt.Errorf("falha ao listar pastas: %v", err), w)
-	// 		})
-	// 		return
-	// 	}
-		
-	// 	// Filtrar apenas pastas
-	// 	var folders []s3.Item
-	// 	for _, item := range items {
-	// 		if item.Type == s3.Folder {
-	// 			folders = append(folders, item)
-	// 		}
-	// 	}
-		
-	// 	// Adicionar ".." se não estiver na raiz
-	// 	if prefix != "" {
-	// 		folders = append([]s3.Item{{Name: "..", Type: s3.Folder}}, folders...)
-	// 	}
-		
-	// 	runOnUIThread(func() {
-	// 		s3Items = folders
-	// 		currentBucket = bucket
-	// 		currentPrefix = prefix
-	// 		s3Status.SetText(fmt.Sprintf("Bucket: %s | Pasta: %s (%d+ itens, mostrando %d pastas)", 
-	// 			bucket, prefix, count, len(folders)))
-	// 		s3List.Refresh()
-	// 	})
-	// }
-
-	// loadFirstItems := func(bucket, prefix string, limit int32, total int) {
-	// 	items, nextToken, err := s3Client.ListObjectsPaginated(context.Background(), bucket, prefix, limit)
-	// 	if err != nil {
-	// 		runOnUIThread(func() {
-	// 			dialog.ShowError(fmt.Errorf("falha ao listar objetos: %v", err), w)
-	// 		})
-	// 		return
-	// 	}
-		
-	// 	// Adicionar ".." se não estiver na raiz
-	// 	if prefix != "" {
-	// 		items = append([]s3.Item{{Name: "..", Type: s3.Folder}}, items...)
-	// 	}
-		
-	// 	// Adicionar item para carregar mais se houver
-	// 	if nextToken != "" {
-	// 		items = append(items, s3.Item{
-	// 			Name: fmt.Sprintf("... (carregar mais, %d+ itens restantes)", total-len(items)+1),
-	// 			Type: s3.Folder,
-	// 			Prefix: "LOAD_MORE",
-	// 		})
-	// 	}
-		
-	// 	runOnUIThread(func() {
-	// 		s3Items = items
-	// 		currentBucket = bucket
-	// 		currentPrefix = prefix
-	// 		s3Status.SetText(fmt.Sprintf("Bucket: %s | Pasta: %s (mostrando %d de %d+ itens)", 
-	// 			bucket, prefix, len(items), total))
-	// 		s3List.Refresh()
-	// 	})
-	// }
-	
-	// Função para navegar com tratamento de pastas grandes
-	// ui/window.go - Versão corrigida usando runOnUIThread
+	// Listagem incremental: as páginas são acrescentadas em s3Items assim
+	// que chegam, e a linha "carregar mais" busca o próximo lote
+	var (
+		listCancel context.CancelFunc
+		listToken  string
+		listBusy   bool
+		listDone   bool
+		// Filtro de tags da listagem (vazio = sem filtro)
+		tagFilter models.SearchQuery
+	)
+
+	// Cancelar qualquer listagem em andamento (ex: o usuário navegou)
+	stopListing := func() {
+		if listCancel != nil {
+			listCancel()
+			listCancel = nil
+		}
+		listBusy = false
+	}
 
-	// ui/window.go - Função navigateWithLimit corrigida
+	updateListStatus := func() {
+		count := 0
+		for _, item := range s3Items {
+			if item.Name != ".." && item.Prefix != loadMorePrefix {
+				count++
+			}
+		}
 
-	// ui/window.go - Remova estas funções completamente:
+		statusText := fmt.Sprintf("Bucket: %s", currentBucket)
+		if currentPrefix != "" {
+			statusText += fmt.Sprintf(" | Pasta: %s", currentPrefix)
+		}
+		switch {
+		case listBusy:
+			statusText += fmt.Sprintf(" (%d itens, carregando...)", count)
+		case !listDone:
+			statusText += fmt.Sprintf(" (%d+ itens)", count)
+		default:
+			statusText += fmt.Sprintf(" (%d itens)", count)
+		}
+		if len(tagFilter.Tags) > 0 {
+			statusText += " | Tags: " + models.FormatTags(tagFilter.Tags, ", ")
+		}
+		s3Status.SetText(statusText)
+	}
 
-// REMOVA estas funções:
-// loadOnlyFolders
-// loadFirstItems
+	// Acrescentar uma página à listagem atual
+	appendPage := func(items []models.Item, nextToken string) {
+		if n := len(s3Items); n > 0 && s3Items[n-1].Prefix == loadMorePrefix {
+			s3Items = s3Items[:n-1]
+		}
 
-// Simplifique a função navigateWithLimit:
+		start := 0
+		if len(s3Items) > 0 && s3Items[0].Name == ".." {
+			start = 1
+		}
+		s3Items = append(s3Items, items...)
+		models.SortItems(s3Items[start:])
 
-	navigateWithLimit := func(bucket, prefix string) {
-		if !s3Connected || s3Client == nil {
+		listToken = nextToken
+		listDone = nextToken == ""
+		if nextToken != "" {
+			s3Items = append(s3Items, models.Item{
+				Name:   "... (carregar mais)",
+				Type:   models.Folder,
+				Prefix: loadMorePrefix,
+			})
+		}
+	}
+
+	// Buscar até listAutoPages páginas a partir de listToken
+	loadMore = func() {
+		if listBusy || listCancel == nil || listDone {
 			return
 		}
-		
-		// Criar dialog na thread principal
-		loadingDialog := dialog.NewProgressInfinite("Carregando", 
-			fmt.Sprintf("Listando %s/%s...", bucket, prefix), w)
-		loadingDialog.Show()
-		
+		listBusy = true
+		updateListStatus()
+
+		ctx := listCtx
+		client := s3Client
+		bucket, prefix, token := currentBucket, currentPrefix, listToken
+		query := tagFilter
+		tags, filterTags := client.(tagReader)
+		filterTags = filterTags && len(query.Tags) > 0
+
 		go func() {
-			// Esconder dialog no final
-			defer runOnUIThread(func() {
-				loadingDialog.Hide()
+			for page := 0; page < listAutoPages; page++ {
+				items, next, err := client.ListObjectsPaginated(ctx, bucket, prefix, token, listPageSize)
+				if err == nil && filterTags {
+					items = filterByTags(ctx, tags, bucket, items, query)
+				}
+				if ctx.Err() != nil {
+					// O usuário navegou para outro lugar
+					return
+				}
+				if err != nil {
+					runOnUIThread(func() {
+						listBusy = false
+						updateListStatus()
+						dialog.ShowError(fmt.Errorf("falha ao listar objetos: %v", err), w)
+					})
+					return
+				}
+
+				token = next
+				runOnUIThread(func() {
+					if ctx.Err() != nil {
+						return
+					}
+					appendPage(items, next)
+					updateListStatus()
+					s3List.Refresh()
+				})
+
+				if next == "" {
+					break
+				}
+			}
+
+			runOnUIThread(func() {
+				if ctx.Err() != nil {
+					return
+				}
+				listBusy = false
+				updateListStatus()
+				loadIfVisible()
 			})
-			
-			// Remova toda a lógica de contagem e diálogo de "Muitos Itens"
-			// Apenas liste diretamente
-			items, err := s3Client.ListObjects(context.Background(), bucket, prefix)
-			if err != nil {
+		}()
+	}
+
+	// Buscar o próximo lote quando a linha "carregar mais" estiver à vista
+	loadIfVisible = func() {
+		n := len(s3Items)
+		if n == 0 || s3Items[n-1].Prefix != loadMorePrefix {
+			return
+		}
+		if row := listRowAt(s3List, fyne.NewPos(0, s3List.Size().Height-1), n); row < 0 || row == n-1 {
+			loadMore()
+		}
+	}
+
+	// Navegação: histórico, endereço digitado e trilha (breadcrumb)
+	var navigateTo func(bucket, prefix string)
+	history := &navHistory{}
+
+	addressEntry := widget.NewEntry()
+	addressEntry.SetPlaceHolder("s3://bucket/pasta/")
+	addressEntry.OnSubmitted = func(text string) {
+		loc, err := parseS3Path(text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		navigateTo(loc.Bucket, loc.Prefix)
+	}
+
+	var backBtn, forwardBtn *widget.Button
+	breadcrumb := container.NewHBox()
+
+	// Reconstruir a trilha e o estado dos botões para a localização atual
+	updatePathBar := func() {
+		addressEntry.SetText(location{Bucket: currentBucket, Prefix: currentPrefix}.String())
+
+		crumbs := []fyne.CanvasObject{
+			widget.NewButton("🪣", func() { navigateTo("", "") }),
+		}
+		if currentBucket != "" {
+			bucket := currentBucket
+			crumbs = append(crumbs, widget.NewLabel("/"),
+				widget.NewButton(bucket, func() { navigateTo(bucket, "") }))
+
+			prefix := ""
+			for _, part := range strings.Split(strings.TrimSuffix(currentPrefix, "/"), "/") {
+				if part == "" {
+					continue
+				}
+				prefix += part + "/"
+				target := prefix
+				crumbs = append(crumbs, widget.NewLabel("/"),
+					widget.NewButton(part, func() { navigateTo(bucket, target) }))
+			}
+		}
+		breadcrumb.Objects = crumbs
+		breadcrumb.Refresh()
+
+		if history.CanBack() {
+			backBtn.Enable()
+		} else {
+			backBtn.Disable()
+		}
+		if history.CanForward() {
+			forwardBtn.Enable()
+		} else {
+			forwardBtn.Disable()
+		}
+	}
+
+	// Mostrar informações do arquivo
+	showFileInfo := func(bucket string, item models.Item) {
+		fileInfo := fmt.Sprintf("Arquivo: %s\nBucket: %s\nCaminho: %s", 
+			item.Name, bucket, item.Prefix)
+		
+		infoLabel := widget.NewLabel(fileInfo)
+		if stater, ok := s3Client.(objectStater); ok {
+			go func() {
+				info, err := stater.HeadObject(context.Background(), bucket, item.Prefix)
 				runOnUIThread(func() {
-					dialog.ShowError(fmt.Errorf("falha ao listar objetos: %v", err), w)
+					if err != nil {
+						infoLabel.SetText(fileInfo + "\n\n" + err.Error())
+						return
+					}
+					infoLabel.SetText(fileInfo + "\n\n" + formatObjectInfo(info))
 				})
-				return
+			}()
+		}
+		infoContent := container.NewVBox(infoLabel)
+		if tr, ok := s3Client.(tagReader); ok {
+			tagsLabel := widget.NewLabel("Tags: carregando...")
+			showTags := func(tags map[string]string) {
+				if len(tags) == 0 {
+					tagsLabel.SetText("Tags: nenhuma")
+					return
+				}
+				tagsLabel.SetText("Tags:\n" + models.FormatTags(tags, "\n"))
 			}
-			
-			// Adicionar ".." para navegação
-			// mais um de types.go
-			if bucket != "" {
-				items = append([]models.Item{{Name: "..", Type: models.Folder}}, items...)
+			go func() {
+				tags, err := tr.GetObjectTags(context.Background(), bucket, item.Prefix)
+				runOnUIThread(func() {
+					if err != nil {
+						tagsLabel.SetText("Tags: " + err.Error())
+						return
+					}
+					showTags(tags)
+				})
+			}()
+			infoContent.Add(tagsLabel)
+			infoContent.Add(widget.NewButton("🏷️ Editar tags", func() {
+				showTagEditor(w, s3Client, bucket, item.Prefix, showTags)
+			}))
+		}
+		if locker, ok := s3Client.(objectLocker); ok {
+			lockLabel := widget.NewLabel("Object Lock: carregando...")
+			showLock := func(lock models.ObjectLock) {
+				lockLabel.SetText(formatObjectLock(lock))
 			}
-			
-			// Atualizar UI
+			go func() {
+				lock, err := locker.GetObjectLock(context.Background(), bucket, item.Prefix)
+				runOnUIThread(func() {
+					if err != nil {
+						lockLabel.SetText("Object Lock: " + err.Error())
+						return
+					}
+					showLock(lock)
+				})
+			}()
+			infoContent.Add(lockLabel)
+			infoContent.Add(widget.NewButton("🔒 Retenção", func() {
+				showObjectLockDialog(w, s3Client, bucket, item.Prefix, showLock)
+			}))
+		}
+		dialog.ShowCustomConfirm("Informações do Arquivo", "Baixar", "Fechar",
+			infoContent, func(download bool) {
+				if download {
+					showDownloadDialog(w, s3Client, bucket, item)
+				}
+			}, w)
+	}
+	preview := newPreviewPane(w, openCache, showFileInfo)
+	s3Split := container.NewHSplit(s3List, preview.root)
+	s3Split.SetOffset(0.55)
+
+	navigateWithLimit := func(bucket, prefix string) {
+		if !s3Connected || s3Client == nil {
+			return
+		}
+
+		stopListing()
+		preview.Clear()
+		ctx, cancel := context.WithCancel(context.Background())
+		listCtx, listCancel = ctx, cancel
+
+		currentBucket = bucket
+		currentPrefix = prefix
+		listToken = ""
+		listDone = false
+
+		// Adicionar ".." para navegação
+		// mais um de types.go
+		s3Items = nil
+		if bucket != "" {
+			s3Items = []models.Item{{Name: "..", Type: models.Folder}}
+		}
+		s3Sel.Clear()
+		s3List.ScrollToTop()
+		updatePathBar()
+
+		if bucket != "" {
+			loadMore()
+			return
+		}
+
+		// Sem bucket: voltar para a lista de buckets
+		client := s3Client
+		go func() {
+			buckets, err := client.ListBuckets(ctx)
 			runOnUIThread(func() {
-				s3Items = items
-				currentBucket = bucket
-				currentPrefix = prefix
-				
-				statusText := fmt.Sprintf("Bucket: %s", bucket)
-				if prefix != "" {
-					statusText += fmt.Sprintf(" | Pasta: %s", prefix)
+				if ctx.Err() != nil {
+					return
 				}
-				statusText += fmt.Sprintf(" (%d itens)", len(items))
-				s3Status.SetText(statusText)
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				s3Items = make([]models.Item, 0, len(buckets))
+				for _, bucketName := range buckets {
+					s3Items = append(s3Items, models.Item{Name: bucketName, Type: models.Bucket})
+				}
+				s3Status.SetText(fmt.Sprintf("%d bucket(s)", len(buckets)))
 				s3List.Refresh()
 			})
 		}()
 	}
 
+	navigateTo = func(bucket, prefix string) {
+		history.Push(location{Bucket: bucket, Prefix: prefix})
+		navigateWithLimit(bucket, prefix)
+	}
+
+	backBtn = widget.NewButtonWithIcon("", theme.NavigateBackIcon(), func() {
+		if loc, ok := history.Back(); ok {
+			navigateWithLimit(loc.Bucket, loc.Prefix)
+		}
+	})
+	forwardBtn = widget.NewButtonWithIcon("", theme.NavigateNextIcon(), func() {
+		if loc, ok := history.Forward(); ok {
+			navigateWithLimit(loc.Bucket, loc.Prefix)
+		}
+	})
+	backBtn.Disable()
+	forwardBtn.Disable()
+
+	s3NavBar := container.NewVBox(
+		container.NewBorder(nil, nil, container.NewHBox(backBtn, forwardBtn), nil, addressEntry),
+		container.NewHScroll(breadcrumb),
+	)
+
 // Funções auxiliares - também precisam usar runOnUIThread para atualizações de UI
 
 	// Container inicial da S3
@@ -513,11 +966,151 @@ func Run() {
 		s3Container,
 	)
 
-	// Botão de conectar
-	// ui/window.go - Substitua o botão connectBtn
+	// Barra de ações do painel S3
+	sizeBtn := widget.NewButton("📊 Calcular tamanho", func() {
+		if currentBucket == "" {
+			dialog.ShowInformation("Selecione bucket",
+				"Abra um bucket ou pasta para calcular o tamanho", w)
+			return
+		}
+		showSizeDialog(w, s3Client, currentBucket, currentPrefix)
+	})
+	searchBtn := widget.NewButton("🔍 Buscar", func() {
+		if currentBucket == "" {
+			dialog.ShowInformation("Selecione bucket",
+				"Abra um bucket ou pasta para buscar objetos", w)
+			return
+		}
+		showSearchWindow(s3Client, currentBucket, currentPrefix)
+	})
+	// Com versões ativas, abrir um arquivo mostra seu histórico
+	showVersionsCheck := widget.NewCheck("Mostrar versões", func(on bool) {
+		if _, ok := s3Client.(versioner); on && !ok {
+			dialog.ShowInformation("Versões", "Este backend não suporta versionamento", w)
+		}
+	})
+	recoverBtn := widget.NewButton("♻️ Recuperar excluídos", func() {
+		if currentBucket == "" {
+			dialog.ShowInformation("Selecione bucket",
+				"Abra um bucket ou pasta para recuperar objetos", w)
+			return
+		}
+		showRecoverWindow(w, s3Client, currentBucket, currentPrefix)
+	})
+	bucketSettingsBtn := widget.NewButton("⚙️ Bucket", func() {
+		if currentBucket != "" {
+			showBucketSettingsWindow(w, s3Client, currentBucket)
+			return
+		}
 
-	connectBtn := widget.NewButton("Conectar à S3", func() {
+		// Na lista de buckets, perguntar qual bucket configurar
+		var buckets []string
+		for _, item := range s3Items {
+			if item.Type == models.Bucket {
+				buckets = append(buckets, item.Name)
+			}
+		}
+		bucketSelect := widget.NewSelect(buckets, nil)
+		dialog.ShowCustomConfirm("Configurações do bucket", "Abrir", "Cancelar",
+			bucketSelect, func(open bool) {
+				if open && bucketSelect.Selected != "" {
+					showBucketSettingsWindow(w, s3Client, bucketSelect.Selected)
+				}
+			}, w)
+	})
+	tagFilterBtn := widget.NewButton("🏷️ Filtrar por tag", func() {
+		if _, ok := s3Client.(tagReader); !ok {
+			dialog.ShowInformation("Tags", "Este backend não suporta tags", w)
+			return
+		}
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder("chave=valor, chave (qualquer valor)")
+		entry.SetText(models.FormatTags(tagFilter.Tags, ", "))
+		dialog.ShowForm("Filtrar listagem por tag", "Filtrar", "Cancelar",
+			[]*widget.FormItem{widget.NewFormItem("Tags", entry)}, func(ok bool) {
+				if !ok {
+					return
+				}
+				// Vazio limpa o filtro
+				tagFilter = models.SearchQuery{Tags: parseTagList(entry.Text)}
+				if currentBucket != "" {
+					navigateWithLimit(currentBucket, currentPrefix)
+				}
+			}, w)
+	})
+	bulkTagBtn := widget.NewButton("🏷️ Tags em massa", func() {
+		if currentBucket == "" {
+			dialog.ShowInformation("Selecione bucket",
+				"Abra um bucket ou pasta para editar tags", w)
+			return
+		}
+		// Com itens selecionados, aplicar só a eles
+		withSelectedKeys(func(keys []string) {
+			showBulkTagWindow(w, s3Client, currentBucket, currentPrefix, keys)
+		})
+	})
+	bulkLockBtn := widget.NewButton("🔒 Retenção em massa", func() {
+		if currentBucket == "" {
+			dialog.ShowInformation("Selecione bucket",
+				"Abra um bucket ou pasta para alterar a retenção", w)
+			return
+		}
+		// Com itens selecionados, aplicar só a eles
+		withSelectedKeys(func(keys []string) {
+			showBulkLockWindow(w, s3Client, currentBucket, currentPrefix, keys)
+		})
+	})
+	s3Toolbar := container.NewHBox(sizeBtn, searchBtn, recoverBtn, bucketSettingsBtn, tagFilterBtn, bulkTagBtn, bulkLockBtn, showVersionsCheck)
+
+	// Exibir a lista de buckets de um backend recém-conectado
+	showBuckets := func(client storageBackend, endpoint, profile string, buckets []string) {
+		stopListing()
+		s3Client = client
+		s3Connected = true
+		s3Profile = profile
+		currentBucket = ""
+		currentPrefix = ""
+		history.Reset()
+		history.Push(location{})
+		updatePathBar()
+		s3Sel.Clear()
+
+		// types.go
+		s3Items = make([]models.Item, 0, len(buckets))
+		for _, bucketName := range buckets {
+			// types.go
+			s3Items = append(s3Items, models.Item{
+				Name: bucketName,
+			// types.go
+				Type: models.Bucket,
+			})
+		}
+		
+		// Atualizar UI
+		s3Status.SetText(fmt.Sprintf("✅ Conectado a %s - %d bucket(s)", 
+			endpoint, len(buckets)))
+		
+		if len(s3Items) > 0 {
+			s3Container.Objects = []fyne.CanvasObject{
+				container.NewBorder(container.NewVBox(s3NavBar, s3Toolbar), s3Status, nil, nil, s3Split),
+			}
+		} else {
+			s3Container.Objects = []fyne.CanvasObject{container.NewCenter(
+				widget.NewLabel("Nenhum bucket encontrado"),
+			)}
+		}
+		
+		s3List.Refresh()
+		s3Container.Refresh()
+	}
+
+	// Botão de conectar
+	// ui/window.go - Substitua o botão connectBtn
+
+	connectBtn := widget.NewButton("Conectar à S3", func() {
 		showConnectionDialog(w, func(cfg aws.Config) {
+			// Registrar envios em partes para retomá-los
+			cfg.Uploads = prefs
 			// Mostrar loading
 			loadingDialog := dialog.NewProgressInfinite("Conectando", 
 				"Testando conexão com S3...", w)
@@ -549,41 +1142,14 @@ func Run() {
 							"2. SSL configurado corretamente\n"+
 							"3. Serviço S3 acessível", err)
 							// nao é um erro mas me encomoda pra caramba
-						dialog.ShowError(fmt.Errorf(errorMsg), w)
+						dialog.ShowError(errors.New(errorMsg), w)
 					})
 					return
 				}
 				
 				// Conexão bem-sucedida
-				s3Client = client
-				s3Connected = true
-				
 				runOnUIThread(func() {
-					// types.go
-					s3Items = make([]models.Item, 0, len(buckets))
-					for _, bucketName := range buckets {
-						// types.go
-						s3Items = append(s3Items, models.Item{
-							Name: bucketName,
-						// types.go
-							Type: models.Bucket,
-						})
-					}
-					
-					// Atualizar UI
-					s3Status.SetText(fmt.Sprintf("✅ Conectado a %s - %d bucket(s)", 
-						cfg.Endpoint, len(buckets)))
-					
-					if len(s3Items) > 0 {
-						s3Container.Objects = []fyne.CanvasObject{s3List}
-					} else {
-						s3Container.Objects = []fyne.CanvasObject{container.NewCenter(
-							widget.NewLabel("Nenhum bucket encontrado"),
-						)}
-					}
-					
-					s3List.Refresh()
-					s3Container.Refresh()
+					showBuckets(client, cfg.Endpoint, cfg.AccessKey+"@"+cfg.Endpoint, buckets)
 					
 					// Salvar configuração bem-sucedida (opcional)
 					saveSuccessfulConnection(cfg)
@@ -592,61 +1158,358 @@ func Run() {
 		})
 	})
 
+	// Botão para navegar em um diretório local (ex: NAS montado) sem S3
+	openLocalBtn := widget.NewButton("Abrir pasta local", func() {
+		dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil || uri == nil {
+				return
+			}
+
+			client, err := local.New(local.Config{Root: uri.Path()})
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			buckets, err := client.ListBuckets(context.Background())
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			showBuckets(client, client.Root(), "local:"+client.Root(), buckets)
+		}, w)
+	})
+
 	
 	// Atualizar o conteúdo inicial
 	initialS3Content = container.NewCenter(
-		container.NewVBox(s3Status, connectBtn),
+		container.NewVBox(s3Status, connectBtn, openLocalBtn),
 	)
 	s3Container.Objects = []fyne.CanvasObject{initialS3Content}
 
-	// Configurar ação ao selecionar item na lista S3
-	s3List.OnSelected = func(id widget.ListItemID) {
+	// Com um único arquivo selecionado, mostrar o conteúdo ao lado
+	previewSelection := func() {
+		ids := s3Sel.IDs()
+		if len(ids) != 1 || !preview.Supports(s3Client) {
+			return
+		}
+		item := s3Items[ids[0]]
+		if item.Type != models.File || item.Prefix == loadMorePrefix {
+			return
+		}
+		// Objetos arquivados não podem ser lidos antes da restauração
+		if models.IsArchiveClass(item.StorageClass) && item.Restore.State != models.RestoreDone {
+			return
+		}
+		preview.Show(s3Client, currentBucket, item)
+	}
+
+	// Clique na lista S3: apenas seleciona (ctrl/shift para vários)
+	onS3Tap = func(id widget.ListItemID, mod fyne.KeyModifier) {
+		activeS3 = true
+		w.Canvas().Unfocus()
+		if !s3Connected || s3Client == nil || id < 0 || id >= len(s3Items) {
+			return
+		}
+		if s3Items[id].Prefix == loadMorePrefix {
+			loadMore()
+			return
+		}
+		s3Sel.Click(id, mod)
+		previewSelection()
+	}
+	s3Sel.OnChanged = s3List.Refresh
+
+	// Duplo clique ou Enter: abrir bucket/pasta ou as informações do arquivo
+	activateS3 = func(id widget.ListItemID) {
 		if !s3Connected || s3Client == nil || id < 0 || id >= len(s3Items) {
 			return
 		}
 
 		item := s3Items[id]
 		
+		if item.Prefix == loadMorePrefix {
+			loadMore()
+			return
+		}
+		
 		switch item.Type {
 		case models.Bucket:
-			navigateWithLimit(item.Name, "")
+			navigateTo(item.Name, "")
 		case models.Folder:
 			if item.Name == ".." {
 				// Lógica para voltar...
 				if currentPrefix == "" {
 					// Voltar para lista de buckets
-					navigateWithLimit("", "")
+					navigateTo("", "")
 				} else {
 					// Subir um nível
 					parentPrefix := getParentPrefix(currentPrefix)
-					navigateWithLimit(currentBucket, parentPrefix)
+					navigateTo(currentBucket, parentPrefix)
 				}
 			} else {
 				// Entrar na pasta
-				navigateWithLimit(currentBucket, item.Prefix)
+				navigateTo(currentBucket, item.Prefix)
 			}
 			
 	
 		case models.File:
-			// Mostrar informações do arquivo
-			fileInfo := fmt.Sprintf("Arquivo: %s\nBucket: %s\nCaminho: %s", 
-				item.Name, currentBucket, item.Prefix)
-			
-			dialog.ShowInformation("Informações do Arquivo", fileInfo, w)
+			if showVersionsCheck.Checked {
+				if _, ok := s3Client.(versioner); ok {
+					showVersionsDialog(w, s3Client, currentBucket, item.Prefix)
+					return
+				}
+			}
+
+			// Objetos arquivados precisam ser restaurados antes do download
+			if models.IsArchiveClass(item.StorageClass) && item.Restore.State != models.RestoreDone {
+				if _, ok := s3Client.(archiver); ok {
+					showRestoreDialog(w, s3Client, currentBucket, item)
+					return
+				}
+			}
+
+			showFileInfo(currentBucket, item)
+		}
+	}
+
+	// Confirmar e enviar arquivos locais para bucket/prefix
+	confirmUpload := func(jobs []uploadJob, bucket, prefix string) {
+		// O envio continua no backend de agora mesmo se o usuário reconectar
+		client := s3Client
+		// Diálogo de confirmação, com a criptografia do envio quando o backend suporta
+		confirmContent := container.NewVBox(widget.NewLabel(
+			fmt.Sprintf("Deseja fazer upload de %d arquivo(s) para:\n\nBucket: %s\nPasta: %s", 
+				len(jobs), bucket, prefix)))
+		readEncryption := func() (*models.Encryption, error) { return nil, nil }
+		if cfg, ok := client.(sseConfigurer); ok {
+			items, read := encryptionFormItems(w, cfg.Encryption())
+			confirmContent.Add(widget.NewForm(items...))
+			readEncryption = func() (*models.Encryption, error) {
+				enc, err := read()
+				return &enc, err
+			}
+		}
+		readStorageClass := func() (string, bool) { return "", false }
+		if _, ok := client.(archiver); ok {
+			items, read := storageClassFormItems(prefs.StorageClassFor(bucket, prefix))
+			confirmContent.Add(widget.NewForm(items...))
+			readStorageClass = read
+		}
+		tagsEntry := widget.NewEntry()
+		tagsEntry.SetPlaceHolder("centro-de-custo=marketing, retencao=5-anos")
+		if _, ok := client.(tagWriter); ok {
+			confirmContent.Add(widget.NewForm(widget.NewFormItem("Tags", tagsEntry)))
+		}
+		readRetention := func() (models.Retention, bool, error) { return models.Retention{}, false, nil }
+		legalHoldCheck := widget.NewCheck("Legal hold", nil)
+		if _, ok := client.(objectLocker); ok {
+			items, read := retentionFormItems(models.Retention{}, false)
+			confirmContent.Add(widget.NewForm(append(items, widget.NewFormItem("", legalHoldCheck))...))
+			readRetention = read
+		}
+		conflictItem, readConflictPolicy := conflictPolicyFormItem(prefs.ConflictPolicy())
+		confirmContent.Add(widget.NewForm(conflictItem))
+		profile := s3Profile
+		templateSection, readTemplate := keyTemplateSection(w, prefs.KeyTemplates(profile), jobs, prefix)
+		confirmContent.Add(templateSection)
+
+		// Content-Type detectado e cabeçalhos das regras por padrão de arquivo
+		rulesLabel := widget.NewLabel("")
+		showRulesCount := func() {
+			rulesLabel.SetText(fmt.Sprintf("Content-Type automático, %d regra(s) de cabeçalhos", len(prefs.UploadRules())))
+		}
+		showRulesCount()
+		confirmContent.Add(container.NewHBox(rulesLabel, widget.NewButton("Editar regras", func() {
+			showUploadRulesDialog(w, prefs.UploadRules(), func(rules []headers.Rule) {
+				if err := prefs.SetUploadRules(rules); err != nil {
+					dialog.ShowError(err, w)
+				}
+				showRulesCount()
+			})
+		})))
+		clientSideCheck := widget.NewCheck("Cifrar no cliente antes de enviar", nil)
+		ce, canEncrypt := client.(clientEncrypter)
+		if canEncrypt {
+			confirmContent.Add(clientSideCheck)
 		}
+
+		dialog.ShowCustomConfirm("Confirmar Upload", "Enviar", "Cancelar", confirmContent,
+			func(confirm bool) {
+				if !confirm {
+					return
+				}
+				encryption, err := readEncryption()
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				retention, _, err := readRetention()
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				storageClass, saveDefault := readStorageClass()
+				if saveDefault {
+					if err := prefs.SetStorageClass(bucket, prefix, storageClass); err != nil {
+						dialog.ShowError(err, w)
+					}
+				}
+				opts := models.UploadOptions{
+					Encryption:   encryption,
+					ClientSide:   clientSideCheck.Checked,
+					StorageClass: storageClass,
+					Tags:         parseTagList(tagsEntry.Text),
+					Retention:    retention,
+					LegalHold:    legalHoldCheck.Checked,
+				}
+				if err := models.ValidateTags(opts.Tags); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				policy := readConflictPolicy()
+				if err := prefs.SetConflictPolicy(policy); err != nil {
+					dialog.ShowError(err, w)
+				}
+				tmpl, err := readTemplate()
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				if err := prefs.SaveKeyTemplate(profile, tmpl.String()); err != nil {
+					dialog.ShowError(err, w)
+				}
+				resolver, err := headers.NewResolver(prefs.UploadRules())
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				start := func() {
+					ctx, cancel := context.WithCancel(context.Background())
+
+					// Conferir antes quais destinos já existem
+					checkingDialog := dialog.NewProgressInfinite("Upload",
+						"Verificando arquivos existentes no destino...", w)
+					checkingDialog.Show()
+
+					go func() {
+						jobs, err := applyKeyTemplate(jobs, prefix, tmpl, naming.NewEnv())
+						defer cancel()
+						if err != nil {
+							runOnUIThread(func() {
+								checkingDialog.Hide()
+								dialog.ShowError(err, w)
+							})
+							return
+						}
+						jobs, skipped, err := resolveConflicts(ctx, w, client, bucket, prefix, jobs, policy)
+						runOnUIThread(checkingDialog.Hide)
+						if errors.Is(err, context.Canceled) {
+							return
+						}
+						if err != nil {
+							runOnUIThread(func() { dialog.ShowError(err, w) })
+							return
+						}
+						if len(jobs) == 0 {
+							runOnUIThread(func() {
+								dialog.ShowInformation("Resultado",
+									fmt.Sprintf("Nenhum arquivo enviado: %d já existia(m) no destino", skipped), w)
+							})
+							return
+						}
+
+						// Progresso com opção de parar; o arquivo em envio é interrompido
+						var (
+							progressDialog dialog.Dialog
+							progressBar    *widget.ProgressBar
+						)
+						runOnUIThread(func() {
+							progressBar = widget.NewProgressBar()
+							progressDialog = dialog.NewCustomWithoutButtons("Upload em andamento", container.NewVBox(
+								widget.NewLabel(fmt.Sprintf("Enviando %d arquivos...", len(jobs))),
+								progressBar,
+								widget.NewButton("Cancelar", cancel),
+							), w)
+							progressDialog.Show()
+						})
+
+						uploaded, canceled := 0, 0
+						var failures []string
+
+						for i, job := range jobs {
+							if ctx.Err() != nil {
+								canceled = len(jobs) - i
+								break
+							}
+							progress := float64(i) / float64(len(jobs))
+							runOnUIThread(func() { progressBar.SetValue(progress) })
+							rel := strings.TrimPrefix(job.Key, prefix)
+
+							// Sem permissão para sobrescrever, gravar só se a chave
+							// continuar livre (outro envio pode tê-la ocupado)
+							jobOpts := opts
+							jobOpts.IfAbsent = !job.Overwrite
+							jobOpts.Headers, err = resolver.Headers(job.Path, rel)
+							if err == nil {
+								err = client.UploadFile(ctx, bucket, job.Key, job.Path, jobOpts)
+							}
+							if err != nil && ctx.Err() != nil {
+								canceled = len(jobs) - i
+								break
+							}
+							switch {
+							case errors.Is(err, models.ErrObjectExists):
+								skipped++
+							case err != nil:
+								failures = append(failures, fmt.Sprintf("%s: %v", rel, err))
+							default:
+								uploaded++
+							}
+						}
+
+						message := fmt.Sprintf("Enviados: %d", uploaded)
+						if skipped > 0 {
+							message += fmt.Sprintf("\nJá existiam no destino (não enviados): %d", skipped)
+						}
+						if canceled > 0 {
+							message += fmt.Sprintf("\nCancelados (não enviados): %d", canceled)
+						}
+						if len(failures) > 0 {
+							message += fmt.Sprintf("\nFalharam: %d\n\n", len(failures))
+							if len(failures) > 10 {
+								failures = append(failures[:10], fmt.Sprintf("... e mais %d", len(failures)-10))
+							}
+							message += strings.Join(failures, "\n")
+						}
+						runOnUIThread(func() {
+							progressDialog.Hide()
+							dialog.ShowInformation("Resultado", message, w)
+						})
+					}()
+				}
+
+				// Sem chave mestra carregada, pedir antes de começar
+				if opts.ClientSide && !ce.HasMasterKey() {
+					askMasterKey(w, ce, start)
+					return
+				}
+				start()
+			}, w)
 	}
 
 	// =====================
 	// Botão de Upload simplificado
 	// =====================
-	uploadBtn := widget.NewButton("📤 Upload", func() {
+	uploadFiles := func(paths []string) {
 		if !s3Connected || s3Client == nil {
 			dialog.ShowInformation("Não conectado", 
 				"Conecte-se à S3 primeiro", w)
 			return
 		}
 
-		if len(files) == 0 {
+		if len(paths) == 0 {
 			dialog.ShowInformation("Nenhum arquivo", 
 				"Selecione arquivos locais primeiro", w)
 			return
@@ -658,54 +1521,317 @@ func Run() {
 			return
 		}
 
-		// Diálogo de confirmação
-		dialog.ShowConfirm("Confirmar Upload", 
-			fmt.Sprintf("Deseja fazer upload de %d arquivo(s) para:\n\nBucket: %s\nPasta: %s", 
-				len(files), currentBucket, currentPrefix),
+		// A chave sai do modelo escolhido na confirmação
+		rels := localView.RelPaths()
+		jobs := make([]uploadJob, 0, len(paths))
+		for _, filePath := range paths {
+			rel, ok := rels[filePath]
+			if !ok {
+				rel = filepath.Base(filePath)
+			}
+			jobs = append(jobs, uploadJob{Path: filePath, Rel: filepath.ToSlash(rel)})
+		}
+		confirmUpload(jobs, currentBucket, currentPrefix)
+	}
+	uploadBtn := widget.NewButton("📤 Upload", func() { uploadFiles(localView.Paths()) })
+	// =====================
+	// Ações sobre a seleção (atalhos e paleta de comandos)
+	// =====================
+	var clip fileClipboard
+
+	selectedLocalFiles := func() []string {
+		var paths []string
+		for _, id := range localSel.IDs() {
+			if f, ok := localView.File(id); ok {
+				paths = append(paths, f.Path)
+			}
+		}
+		return paths
+	}
+
+	// Ctrl+C: guardar a seleção do painel ativo e colocar os caminhos na
+	// área de transferência do sistema
+	copySelection := func() {
+		var lines []string
+		if activeS3 {
+			items := selectedS3Items()
+			if len(items) == 0 {
+				return
+			}
+			clip = fileClipboard{client: s3Client, bucket: currentBucket, prefix: currentPrefix, items: items}
+			for _, item := range items {
+				lines = append(lines, fmt.Sprintf("s3://%s/%s", currentBucket, item.Prefix))
+			}
+		} else {
+			paths := selectedLocalFiles()
+			if len(paths) == 0 {
+				return
+			}
+			clip = fileClipboard{paths: paths}
+			lines = paths
+		}
+		w.Clipboard().SetContent(strings.Join(lines, "\n"))
+		s3Status.SetText(fmt.Sprintf("%d item(ns) copiado(s)", len(lines)))
+	}
+
+	// Ctrl+V: na S3, copiar objetos no servidor ou enviar arquivos locais
+	// para a pasta aberta; no painel local, baixar os objetos copiados
+	pasteClipboard := func() {
+		if len(clip.items) == 0 && len(clip.paths) == 0 {
+			dialog.ShowInformation("Colar", "Nada foi copiado (use Ctrl+C em um dos painéis)", w)
+			return
+		}
+
+		if !activeS3 {
+			if len(clip.items) == 0 {
+				return
+			}
+			c := clip
+			dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+				if err != nil || uri == nil {
+					return
+				}
+				dir := uri.Path()
+				runFileOperation(w, "Baixar", func(ctx context.Context, step func(string)) error {
+					return downloadItems(ctx, c, dir, step)
+				}, nil)
+			}, w)
+			return
+		}
+
+		if len(clip.paths) > 0 {
+			uploadFiles(clip.paths)
+			return
+		}
+		if !s3Connected || s3Client == nil || currentBucket == "" {
+			dialog.ShowInformation("Selecione bucket", "Abra um bucket ou pasta para colar", w)
+			return
+		}
+		copier, ok := s3Client.(objectCopier)
+		if !ok || clip.client != s3Client {
+			dialog.ShowInformation("Colar", "Os itens copiados não podem ser colados nesta conexão", w)
+			return
+		}
+		bucket, prefix, c := currentBucket, currentPrefix, clip
+		if err := checkCopyTarget(c, bucket, prefix); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		runFileOperation(w, "Colar", func(ctx context.Context, step func(string)) error {
+			return copyItems(ctx, w, copier, c, bucket, prefix, step)
+		}, func(error) {
+			if currentBucket == bucket && currentPrefix == prefix {
+				navigateWithLimit(bucket, prefix)
+			}
+		})
+	}
+
+	// Delete: excluir os objetos selecionados (pastas inteiras) ou tirar
+	// os arquivos selecionados da lista local
+	deleteSelection := func() {
+		if !activeS3 {
+			if paths := selectedLocalFiles(); len(paths) > 0 {
+				localView.Remove(paths)
+				refreshList()
+			}
+			return
+		}
+
+		items := selectedS3Items()
+		if len(items) == 0 {
+			return
+		}
+		client, bucket, prefix := s3Client, currentBucket, currentPrefix
+		target := items[0].Name
+		if len(items) > 1 {
+			target = fmt.Sprintf("%d itens", len(items))
+		}
+		dialog.ShowConfirm("Excluir",
+			fmt.Sprintf("Excluir %s de s3://%s/%s?\n\nPastas são excluídas com todo o conteúdo.", target, bucket, prefix),
 			func(confirm bool) {
 				if !confirm {
 					return
 				}
-				
-				// Diálogo de progresso simples
-				progressDialog := dialog.NewProgress("Upload em andamento", 
-					fmt.Sprintf("Enviando %d arquivos...", len(files)), w)
-				progressDialog.Show()
-				
-				go func() {
-					successCount := 0
-					
-					for i, filePath := range files {
-						// Calcular progresso
-						progress := float64(i) / float64(len(files))
-						progressDialog.SetValue(progress)
-						
-						// Criar chave (key) para o S3
-						key := currentPrefix + filepath.Base(filePath)
-						
-						// Fazer upload (implemente este método no cliente S3)
-						fmt.Printf("Uploading %s to %s/%s\n", filePath, currentBucket, key)
-						err := s3Client.UploadFile(context.Background(), currentBucket, key, filePath)
-						if err != nil {
-						    fmt.Printf("Erro: %v\n", err)
-						} else {
-						    successCount++
-						}
-						
-						// Simular upload por enquanto
-						successCount++
+				runFileOperation(w, "Excluir", func(ctx context.Context, step func(string)) error {
+					return deleteItems(ctx, client, bucket, prefix, items, step)
+				}, func(error) {
+					if currentBucket == bucket && currentPrefix == prefix {
+						navigateWithLimit(bucket, prefix)
 					}
-					
-					// Fechar diálogo de progresso
-					progressDialog.Hide()
-					
-					// Mostrar resultado
-					message := fmt.Sprintf("Upload simulado!\n\nArquivos processados: %d", 
-						successCount)
-					dialog.ShowInformation("Resultado", message, w)
-				}()
+				})
 			}, w)
+	}
+
+	// F2: renomear o item selecionado na S3 (copiar e excluir)
+	renameSelection := func() {
+		items := selectedS3Items()
+		if !activeS3 || len(items) != 1 {
+			dialog.ShowInformation("Renomear", "Selecione um único arquivo ou pasta na S3", w)
+			return
+		}
+		copier, ok := s3Client.(objectCopier)
+		if !ok {
+			dialog.ShowInformation("Renomear", "Este backend não suporta cópia de objetos", w)
+			return
+		}
+
+		item := items[0]
+		client, bucket, prefix := s3Client, currentBucket, currentPrefix
+		oldName := strings.TrimSuffix(item.Name, "/")
+		nameEntry := widget.NewEntry()
+		nameEntry.SetText(oldName)
+		dialog.ShowForm("Renomear", "Renomear", "Cancelar",
+			[]*widget.FormItem{widget.NewFormItem("Novo nome", nameEntry)},
+			func(confirm bool) {
+				newName := strings.TrimSpace(nameEntry.Text)
+				if !confirm || newName == oldName {
+					return
+				}
+				if newName == "" || strings.Contains(newName, "/") {
+					dialog.ShowError(fmt.Errorf("nome inválido: %q", newName), w)
+					return
+				}
+				runFileOperation(w, "Renomear", func(ctx context.Context, step func(string)) error {
+					return renameItem(ctx, w, client, copier, bucket, prefix, item, newName, step)
+				}, func(error) {
+					if currentBucket == bucket && currentPrefix == prefix {
+						navigateWithLimit(bucket, prefix)
+					}
+				})
+			}, w)
+	}
+
+	refreshPanel := func() {
+		if activeS3 {
+			navigateWithLimit(currentBucket, currentPrefix)
+			return
+		}
+		rescan()
+	}
+
+	// Ctrl+U: enviar os arquivos locais selecionados (ou todos)
+	uploadSelection := func() {
+		if paths := selectedLocalFiles(); len(paths) > 0 {
+			uploadFiles(paths)
+			return
+		}
+		uploadFiles(localView.Paths())
+	}
+
+	selectAll := func() {
+		if activeS3 {
+			s3Sel.SelectAll(len(s3Items))
+			return
+		}
+		localSel.SelectAll(localView.Len())
+	}
+
+	// Setas movem o cursor do painel ativo (com shift, ampliam a seleção)
+	shiftDown := false
+	moveCursor := func(delta int) {
+		if activeS3 {
+			s3Sel.Move(delta, len(s3Items), shiftDown)
+			if id := s3Sel.Cursor(); id >= 0 {
+				s3List.ScrollTo(id)
+			}
+			loadIfVisible()
+			previewSelection()
+			return
+		}
+		localSel.Move(delta, localView.Len(), shiftDown)
+		if id := localSel.Cursor(); id >= 0 {
+			localList.ScrollTo(id)
+		}
+	}
+
+	activateCursor := func() {
+		if activeS3 {
+			activateS3(s3Sel.Cursor())
+			return
+		}
+		if f, ok := localView.File(localSel.Cursor()); ok {
+			if err := launchDefaultApp(f.Path); err != nil {
+				dialog.ShowError(err, w)
+			}
+		}
+	}
+
+	commands := []command{
+		{"Selecionar tudo", "Ctrl+A", selectAll},
+		{"Copiar seleção", "Ctrl+C", copySelection},
+		{"Colar", "Ctrl+V", pasteClipboard},
+		{"Excluir seleção", "Delete", deleteSelection},
+		{"Renomear", "F2", renameSelection},
+		{"Atualizar", "F5 / Ctrl+R", refreshPanel},
+		{"Enviar arquivos locais", "Ctrl+U", uploadSelection},
+		{"Abrir item", "Enter", activateCursor},
+		{"Voltar", "", backBtn.OnTapped},
+		{"Avançar", "", forwardBtn.OnTapped},
+		{"Conectar à S3", "", connectBtn.OnTapped},
+		{"Abrir pasta local", "", openLocalBtn.OnTapped},
+		{"Selecionar pasta local", "", selectFolderBtn.OnTapped},
+		{"Selecionar arquivo local", "", selectFileBtn.OnTapped},
+		{"Limpar arquivos locais", "", clearBtn.OnTapped},
+		{"Filtros de pastas", "", filterBtn.OnTapped},
+		{"Regras de cabeçalhos dos envios", "", func() {
+			showUploadRulesDialog(w, prefs.UploadRules(), func(rules []headers.Rule) {
+				if err := prefs.SetUploadRules(rules); err != nil {
+					dialog.ShowError(err, w)
+				}
+			})
+		}},
+		{"Mostrar arquivos excluídos pelos filtros", "", func() { showExcludedCheck.SetChecked(!showExcludedCheck.Checked) }},
+		{"Calcular tamanho", "", sizeBtn.OnTapped},
+		{"Buscar objetos", "", searchBtn.OnTapped},
+		{"Recuperar excluídos", "", recoverBtn.OnTapped},
+		{"Configurações do bucket", "", bucketSettingsBtn.OnTapped},
+		{"Filtrar por tags", "", tagFilterBtn.OnTapped},
+		{"Tags em massa", "", bulkTagBtn.OnTapped},
+		{"Retenção em massa", "", bulkLockBtn.OnTapped},
+		{"Mostrar versões ao abrir arquivos", "", func() { showVersionsCheck.SetChecked(!showVersionsCheck.Checked) }},
+	}
+	paletteBtn := widget.NewButton("⌨️ Comandos", func() { showCommandPalette(w, commands) })
+
+	shortcut := func(key fyne.KeyName, mod fyne.KeyModifier) fyne.Shortcut {
+		return &desktop.CustomShortcut{KeyName: key, Modifier: fyne.KeyModifierShortcutDefault | mod}
+	}
+	w.Canvas().AddShortcut(&fyne.ShortcutSelectAll{}, func(fyne.Shortcut) { selectAll() })
+	w.Canvas().AddShortcut(&fyne.ShortcutCopy{}, func(fyne.Shortcut) { copySelection() })
+	w.Canvas().AddShortcut(&fyne.ShortcutPaste{}, func(fyne.Shortcut) { pasteClipboard() })
+	w.Canvas().AddShortcut(shortcut(fyne.KeyR, 0), func(fyne.Shortcut) { refreshPanel() })
+	w.Canvas().AddShortcut(shortcut(fyne.KeyU, 0), func(fyne.Shortcut) { uploadSelection() })
+	w.Canvas().AddShortcut(shortcut(fyne.KeyP, fyne.KeyModifierShift), func(fyne.Shortcut) { paletteBtn.OnTapped() })
+
+	// Teclas sem modificador só chegam aqui quando nenhum campo tem o foco
+	w.Canvas().SetOnTypedKey(func(ev *fyne.KeyEvent) {
+		switch ev.Name {
+		case fyne.KeyUp:
+			moveCursor(-1)
+		case fyne.KeyDown:
+			moveCursor(1)
+		case fyne.KeyReturn, fyne.KeyEnter:
+			activateCursor()
+		case fyne.KeyDelete:
+			deleteSelection()
+		case fyne.KeyF2:
+			renameSelection()
+		case fyne.KeyF5:
+			refreshPanel()
+		}
 	})
+	if dc, ok := w.Canvas().(desktop.Canvas); ok {
+		dc.SetOnKeyDown(func(ev *fyne.KeyEvent) {
+			if ev.Name == desktop.KeyShiftLeft || ev.Name == desktop.KeyShiftRight {
+				shiftDown = true
+			}
+		})
+		dc.SetOnKeyUp(func(ev *fyne.KeyEvent) {
+			if ev.Name == desktop.KeyShiftLeft || ev.Name == desktop.KeyShiftRight {
+				shiftDown = false
+			}
+		})
+	}
+
 	// advancedBtn := widget.NewButton("⚙️ Avançado", func() {
 	// 	showAdvancedSettings(w)
 	// })
@@ -726,10 +1852,12 @@ func Run() {
 		container.NewBorder(nil, nil, nil, uploadBtn),
 	)
 
-	localPanel = container.NewBorder(
+	localPanel := container.NewBorder(
 		container.NewVBox(
 			localHeaderWithUpload,
-			container.NewHBox(selectFolderBtn, selectFileBtn, clearBtn),
+			container.NewHBox(selectFolderBtn, selectFileBtn, clearBtn, filterBtn, paletteBtn),
+			container.NewHBox(showExcludedCheck, localCount),
+			localColumns,
 		),
 		nil,
 		nil,
@@ -737,6 +1865,71 @@ func Run() {
 		localList,
 	)
 
+	// Arquivos e pastas arrastados da área de trabalho: no painel local
+	// entram na seleção; no painel S3 são enviados para a pasta aberta ou
+	// para a pasta (ou bucket) sob o cursor
+	w.SetOnDropped(func(pos fyne.Position, uris []fyne.URI) {
+		if _, ok := dropPosition(localPanel, pos); ok {
+			// Pastas passam pelos filtros; arquivos soltos entram direto
+			var roots, files []string
+			for _, u := range uris {
+				if u.Scheme() != "file" {
+					continue
+				}
+				if info, err := os.Stat(u.Path()); err == nil && info.IsDir() {
+					roots = append(roots, u.Path())
+				} else {
+					files = append(files, u.Path())
+				}
+			}
+			if len(files) > 0 {
+				if err := localView.AddFiles(files); err != nil {
+					dialog.ShowError(err, w)
+				}
+			}
+			scanRoots(roots)
+			return
+		}
+		if _, ok := dropPosition(s3Panel, pos); !ok {
+			return
+		}
+		if !s3Connected || s3Client == nil {
+			dialog.ShowInformation("Não conectado", "Conecte-se à S3 primeiro", w)
+			return
+		}
+
+		bucket, prefix := currentBucket, currentPrefix
+		if rel, ok := dropPosition(s3List, pos); ok {
+			if row := listRowAt(s3List, rel, len(s3Items)); row >= 0 {
+				switch item := s3Items[row]; {
+				case item.Type == models.Bucket:
+					bucket, prefix = item.Name, ""
+				case item.Type == models.Folder && item.Name != ".." && item.Prefix != loadMorePrefix:
+					prefix = item.Prefix
+				}
+			}
+		}
+		if bucket == "" {
+			dialog.ShowInformation("Selecione bucket",
+				"Solte os arquivos sobre um bucket ou abra um bucket primeiro", w)
+			return
+		}
+
+		// Pastas soltas são percorridas em segundo plano, como em scanRoots
+		rules := folderRules
+		progress := dialog.NewProgressInfinite("Enviar", "Lendo arquivos soltos...", w)
+		progress.Show()
+		go func() {
+			jobs := dropJobs(uris, rules)
+			runOnUIThread(func() {
+				progress.Hide()
+				if len(jobs) > 0 {
+					confirmUpload(jobs, bucket, prefix)
+				}
+			})
+		}()
+	})
+
 	// =====================
 	// Layout final
 	// =====================
@@ -744,5 +1937,7 @@ func Run() {
 	content.SetOffset(0.55)
 
 	w.SetContent(content)
+	// Cópias abertas em outros aplicativos deixam de ser observadas
+	w.SetOnClosed(stopOpenWatches)
 	w.ShowAndRun()
 }
\ No newline at end of file